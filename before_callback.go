@@ -27,6 +27,11 @@ type BeforeCallbackInfo struct {
 	// Name is the name of the function in the format:
 	// <package_name>.<function_name>
 	Name string
+
+	// Attempt is the number of the call to the function, starting at 1.
+	// It's only greater than 1 for the retries of a constructor provided
+	// with the [Retry] option.
+	Attempt int
 }
 
 // BeforeCallback is a function that can be registered with a provided function
@@ -0,0 +1,399 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"runtime"
+	"strconv"
+	"sync"
+
+	"go.uber.org/dig/internal/digreflect"
+)
+
+// errCallPanicked is reported to goroutines that waited on a call which
+// panicked instead of returning.
+var errCallPanicked = errors.New("panicked while being called by another goroutine")
+
+// buildState records the constructors and decorators that are currently
+// running on a single call path while the dependencies of an invoked
+// function are being built.
+//
+// buildStates are immutable: push returns a new buildState and leaves the
+// receiver untouched. This allows the same state to be shared by
+// independent branches of a build. A nil *buildState is an empty state.
+type buildState struct {
+	parent *buildState
+
+	// node is the *constructorNode or *decoratorNode that was running
+	// when this state was pushed.
+	node interface{}
+
+	// log records the changes made on behalf of the Invoke this state
+	// belongs to. It's shared by all states of a build.
+	log *buildLog
+
+	// span is the trace span of the Invoke, constructor or decorator
+	// running on this call path, if the Container has a Tracer.
+	span *traceSpan
+
+	// requested is the parameter being built on this call path for span,
+	// if any.
+	requested *Input
+
+	// ctx is the context that values are built with on this call path.
+	ctx context.Context
+
+	// failures records the calls that failed during the build if the
+	// Invoke was made with CollectErrors. It's shared by all states of a
+	// build.
+	failures *buildFailures
+}
+
+// newBuildState returns an empty buildState for a new Invoke made with
+// the given context. The build is tracked by t until it's finished.
+func newBuildState(ctx context.Context, t *buildTracker) *buildState {
+	return &buildState{log: t.start(), ctx: ctx}
+}
+
+// push returns a buildState that records n as running on top of bs.
+func (bs *buildState) push(n interface{}) *buildState {
+	if bs == nil {
+		return &buildState{node: n}
+	}
+	return &buildState{
+		parent:   bs,
+		node:     n,
+		log:      bs.log,
+		span:     bs.span,
+		ctx:      bs.ctx,
+		failures: bs.failures,
+	}
+}
+
+// collectingErrors returns a buildState that keeps building independent
+// values after a failure, and records failed calls so that they aren't
+// made again.
+func (bs *buildState) collectingErrors() *buildState {
+	collecting := *bs
+	collecting.failures = &buildFailures{errs: make(map[*callGate]error)}
+	return &collecting
+}
+
+// collectsErrors reports whether the build keeps going after a failure.
+func (bs *buildState) collectsErrors() bool {
+	return bs != nil && bs.failures != nil
+}
+
+// failed returns the error of the call through g if it already failed
+// during this build. Failures are only recorded while collecting errors.
+func (bs *buildState) failed(g *callGate) (error, bool) {
+	if !bs.collectsErrors() {
+		return nil, false
+	}
+	return bs.failures.get(g)
+}
+
+// fail records that the call through g failed with err, if the build
+// collects errors.
+func (bs *buildState) fail(g *callGate, err error) {
+	if bs.collectsErrors() {
+		bs.failures.add(g, err)
+	}
+}
+
+// within returns a buildState that records span as the trace span running
+// on top of bs. It returns bs as is if span is nil.
+func (bs *buildState) within(span *traceSpan) *buildState {
+	if span == nil {
+		return bs
+	}
+	within := *bs
+	within.span, within.requested = span, nil
+	return &within
+}
+
+// requesting returns a buildState that records in as the parameter being
+// built by the span running on top of bs. This is only needed for tracing,
+// so it returns bs as is if there's no such span.
+func (bs *buildState) requesting(in Input) *buildState {
+	if bs == nil || bs.span == nil {
+		return bs
+	}
+	requesting := *bs
+	requesting.requested = &in
+	return &requesting
+}
+
+// context returns the context that values are built with on this call
+// path.
+func (bs *buildState) context() context.Context {
+	if bs == nil || bs.ctx == nil {
+		return context.Background()
+	}
+	return bs.ctx
+}
+
+// checkContext returns a CanceledError for the function f if the context
+// of this call path is done.
+func (bs *buildState) checkContext(f *digreflect.Func) error {
+	if err := bs.context().Err(); err != nil {
+		return CanceledError{Location: newLocation(f), Err: err}
+	}
+	return nil
+}
+
+// cycleError reports that n was requested again while it was already
+// running. This is only possible through a Lazy, or if n calls into the
+// container to build its own value, in which case the first call to n is
+// not on this call path.
+func (bs *buildState) cycleError(n *constructorNode, s *Scope) error {
+	entry := func(n *constructorNode) cycleErrPathEntry {
+		return cycleErrPathEntry{Key: key{t: n.CType()}, Func: n.Location()}
+	}
+
+	// Collect the path from the most recent constructor back to the
+	// first call to n, then reverse it.
+	path := []cycleErrPathEntry{entry(n)}
+	for ; bs != nil; bs = bs.parent {
+		cn, ok := bs.node.(*constructorNode)
+		if !ok {
+			continue
+		}
+		path = append(path, entry(cn))
+		if cn == n {
+			break
+		}
+	}
+	if bs == nil {
+		path = append(path, entry(n))
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return errCycleDetected{Path: path, scope: s}
+}
+
+// record adds a change to the log of this build, if any.
+func (bs *buildState) record(undo func() []*cleanup, hasCleanups bool) {
+	if bs != nil {
+		bs.log.record(undo, hasCleanups)
+	}
+}
+
+// rollback undoes the changes recorded for this build after it failed
+// with reason, if needed. See buildLog.rollback.
+func (bs *buildState) rollback(reason error) error {
+	if bs == nil {
+		return reason
+	}
+	return bs.log.rollback(reason)
+}
+
+// finish marks the build that bs belongs to as over.
+func (bs *buildState) finish() {
+	if bs != nil {
+		bs.log.finish()
+	}
+}
+
+// finished reports whether the build that bs belongs to is over.
+func (bs *buildState) finished() bool {
+	return bs != nil && bs.log.finished()
+}
+
+// detached returns a buildState for a new build tracked by t, with the
+// same nodes running as bs but none of the context, log or trace spans of
+// its build.
+func (bs *buildState) detached(t *buildTracker) *buildState {
+	detached := newBuildState(context.Background(), t)
+	var nodes []interface{}
+	for ; bs != nil; bs = bs.parent {
+		if bs.node != nil {
+			nodes = append(nodes, bs.node)
+		}
+	}
+	for i := len(nodes) - 1; i >= 0; i-- {
+		detached = detached.push(nodes[i])
+	}
+	return detached
+}
+
+// has reports whether n is running on this call path.
+func (bs *buildState) has(n interface{}) bool {
+	for ; bs != nil; bs = bs.parent {
+		if bs.node != nil && bs.node == n {
+			return true
+		}
+	}
+	return false
+}
+
+// buildFailures records the errors of the calls that failed during a
+// build.
+type buildFailures struct {
+	mu   sync.Mutex
+	errs map[*callGate]error
+}
+
+func (f *buildFailures) get(g *callGate) (error, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	err, ok := f.errs[g]
+	return err, ok
+}
+
+func (f *buildFailures) add(g *callGate, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.errs[g] = err
+}
+
+// callGate ensures that the function owned by a constructor or decorator
+// node is run successfully at most once, even if the node is called from
+// multiple goroutines at the same time.
+//
+// Callers that find a call already in progress wait for it to finish and
+// share its outcome, similarly to golang.org/x/sync/singleflight.
+type callGate struct {
+	mu sync.Mutex
+
+	// Whether the function was already called successfully.
+	called bool
+
+	// Call that is currently in progress, if any.
+	inflight *gateCall
+}
+
+// gateCall is a single in-progress call through a callGate.
+type gateCall struct {
+	done chan struct{} // closed when the call finishes
+	err  error         // outcome of the call; valid after done is closed
+
+	// goroutine is the goroutine that the owner of the call acts for.
+	goroutine uint64
+}
+
+// enter reports how the caller should proceed with a call.
+//
+// If the function was already called, enter returns nil. Otherwise, it
+// returns the call in progress and whether the caller owns it. Owners must
+// run the function and report its outcome with exit. Other callers must
+// wait on the returned call instead.
+func (g *callGate) enter() (call *gateCall, owner bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.called {
+		return nil, false
+	}
+	if g.inflight != nil {
+		return g.inflight, false
+	}
+
+	g.inflight = &gateCall{done: make(chan struct{}), goroutine: actingGoroutine()}
+	return g.inflight, true
+}
+
+// exit records the outcome of a call owned by the caller and releases
+// everyone waiting on it. Failed calls may be attempted again later.
+//
+// panicked is the value recovered from the call, if it panicked. The call
+// is then reported as failed and the panic is propagated.
+func (g *callGate) exit(call *gateCall, err error, panicked interface{}) {
+	if panicked != nil {
+		err = errCallPanicked
+	}
+
+	g.mu.Lock()
+	g.called = err == nil
+	g.inflight = nil
+	g.mu.Unlock()
+
+	call.err = err
+	close(call.done)
+
+	if panicked != nil {
+		panic(panicked)
+	}
+}
+
+// reset allows the function to be called again after it was called
+// successfully.
+func (g *callGate) reset() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.called = false
+}
+
+// used reports whether the function was called successfully or is being
+// called right now.
+func (g *callGate) used() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.called || g.inflight != nil
+}
+
+// wait blocks until the call is finished and returns its outcome.
+func (call *gateCall) wait() error {
+	<-call.done
+	return call.err
+}
+
+// reentrant reports whether the caller is the owner of the call, which
+// reached it again from within the function it's running, for example by
+// invoking the container. Waiting on the call would then never end.
+func (call *gateCall) reentrant() bool {
+	return actingGoroutine() == call.goroutine
+}
+
+// _actingFor maps the goroutines that run constructors with a Timeout to
+// the goroutines that they run them for.
+var _actingFor sync.Map // map[uint64]uint64
+
+// actingGoroutine returns the ID of the goroutine that the current
+// goroutine acts for: the goroutine that started it to run a constructor
+// with a Timeout, or itself.
+func actingGoroutine() uint64 {
+	id := goroutineID()
+	if parent, ok := _actingFor.Load(id); ok {
+		return parent.(uint64)
+	}
+	return id
+}
+
+// goroutineID returns the ID of the current goroutine. The runtime only
+// exposes it in stack traces, which start with "goroutine <ID> [".
+func goroutineID() uint64 {
+	var buf [64]byte
+	b := bytes.TrimPrefix(buf[:runtime.Stack(buf[:], false)], []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i >= 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildState(t *testing.T) {
+	t.Parallel()
+
+	var empty *buildState
+	assert.False(t, empty.has(1))
+
+	a := empty.push(1)
+	b := a.push(2)
+	c := a.push(3)
+
+	assert.True(t, b.has(1))
+	assert.True(t, b.has(2))
+	assert.False(t, b.has(3))
+	assert.True(t, c.has(3))
+	assert.False(t, c.has(2))
+	assert.False(t, a.has(2), "push must not modify the receiver")
+
+	t.Run("finished", func(t *testing.T) {
+		t.Parallel()
+
+		var builds buildTracker
+		bs := newBuildState(context.Background(), &builds).push(1).push(2)
+		bs.record(func() []*cleanup { return nil }, false /* hasCleanups */)
+		assert.False(t, bs.finished())
+
+		bs.finish()
+		assert.True(t, bs.finished())
+		assert.Empty(t, bs.log.undo, "finish must drop the log")
+
+		bs.record(func() []*cleanup { return nil }, true /* hasCleanups */)
+		assert.Empty(t, bs.log.undo, "nothing may be recorded after the build")
+		assert.Empty(t, builds.running)
+
+		detached := bs.detached(&builds)
+		assert.True(t, detached.has(1))
+		assert.True(t, detached.has(2))
+		assert.NotSame(t, bs.log, detached.log)
+		assert.False(t, detached.finished())
+		assert.Len(t, builds.running, 1)
+	})
+
+	t.Run("overlapping builds", func(t *testing.T) {
+		t.Parallel()
+
+		var builds buildTracker
+		a := newBuildState(context.Background(), &builds)
+		assert.False(t, a.log.overlapped)
+		a.finish()
+
+		b := newBuildState(context.Background(), &builds)
+		assert.False(t, b.log.overlapped, "finished builds must not overlap")
+
+		c := newBuildState(context.Background(), &builds)
+		assert.True(t, b.log.overlapped)
+		assert.True(t, c.log.overlapped)
+	})
+}
+
+func TestCallGate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		var g callGate
+		call, owner := g.enter()
+		require.NotNil(t, call)
+		assert.True(t, owner)
+
+		waiter, owner := g.enter()
+		assert.Same(t, call, waiter)
+		assert.False(t, owner)
+
+		g.exit(call, nil, nil /* panicked */)
+		assert.NoError(t, waiter.wait())
+		assert.True(t, g.called)
+
+		call, _ = g.enter()
+		assert.Nil(t, call, "gate must not be entered after a success")
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		t.Parallel()
+
+		var g callGate
+		call, _ := g.enter()
+		waiter, _ := g.enter()
+
+		g.exit(call, errors.New("great sadness"), nil /* panicked */)
+		assert.EqualError(t, waiter.wait(), "great sadness")
+		assert.False(t, g.called)
+
+		call, owner := g.enter()
+		require.NotNil(t, call, "gate must be entered again after a failure")
+		assert.True(t, owner)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		t.Parallel()
+
+		var g callGate
+		call, _ := g.enter()
+		waiter, _ := g.enter()
+
+		assert.PanicsWithValue(t, "great sadness", func() {
+			g.exit(call, nil, "great sadness")
+		})
+		assert.ErrorIs(t, waiter.wait(), errCallPanicked)
+		assert.False(t, g.called)
+	})
+}
@@ -38,6 +38,11 @@ type CallbackInfo struct {
 	// Runtime contains the duration it took for the associated
 	// function to run.
 	Runtime time.Duration
+
+	// Attempt is the number of the call to the function, starting at 1.
+	// It's only greater than 1 for the retries of a constructor provided
+	// with the [Retry] option.
+	Attempt int
 }
 
 // Callback is a function that can be registered with a provided function
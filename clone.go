@@ -0,0 +1,233 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig
+
+import (
+	"math/rand"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Clone returns a copy of the Container with the same constructors,
+// decorators and Scopes, but none of the values that were built.
+//
+// Changes made to the copy, such as providing or replacing constructors,
+// do not affect the original Container and vice versa. This is useful to
+// wire an application once and give each test a pristine copy of it.
+//
+//	c := buildContainer()
+//
+//	func TestServer(t *testing.T) {
+//	  c := c.Clone()
+//	  c.Replace(newFakeDatabase)
+//	  ...
+//	}
+func (c *Container) Clone() *Container {
+	return &Container{scope: c.scope.Clone()}
+}
+
+// Clone returns a copy of the Scope and its descendants with the same
+// constructors and decorators, but none of the values that were built.
+//
+// The copy is a new child of the parent of this Scope, so it keeps using
+// the constructors provided to its ancestors and the values they built.
+// Cloning the root Scope is equivalent to cloning the Container.
+func (s *Scope) Clone() *Scope {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sc := scopeCloner{
+		scopes: make(map[*Scope]*Scope),
+		ctors:  make(map[*constructorNode]*constructorNode),
+		dcors:  make(map[*decoratorNode]*decoratorNode),
+	}
+	clone := sc.cloneScope(s, s.parentScope)
+	if parent := s.parentScope; parent != nil {
+		parent.childScopes = append(parent.childScopes, clone)
+	}
+	sc.cloneGraphs()
+	return clone
+}
+
+// scopeCloner copies a tree of Scopes along with the nodes provided to it.
+// Nodes provided to Scopes outside the tree are shared with the copy.
+type scopeCloner struct {
+	// Mapping from the original Scopes to their copies.
+	scopes map[*Scope]*Scope
+
+	// Mapping from the original nodes to their copies.
+	ctors map[*constructorNode]*constructorNode
+	dcors map[*decoratorNode]*decoratorNode
+}
+
+// cloneScope copies s and its descendants, attaching the copy to parent.
+// The graphs of the copies are filled in later by cloneGraphs, once all
+// Scopes are known.
+func (sc *scopeCloner) cloneScope(s, parent *Scope) *Scope {
+	clone := &Scope{
+		name:                     s.name,
+		decorators:               make(map[key]*decoratorNode, len(s.decorators)),
+		providers:                make(map[key][]*constructorNode, len(s.providers)),
+		values:                   make(map[key]reflect.Value),
+		scopedValues:             make(map[key]reflect.Value),
+		decoratedValues:          make(map[key]reflect.Value),
+		groups:                   make(map[key][]reflect.Value),
+		decoratedGroups:          make(map[key]reflect.Value),
+		rand:                     rand.New(rand.NewSource(time.Now().UnixNano())),
+		isVerifiedAcyclic:        s.isVerifiedAcyclic,
+		deferAcyclicVerification: s.deferAcyclicVerification,
+		recoverFromPanics:        s.recoverFromPanics,
+		invokerFn:                s.invokerFn,
+		parentScope:              parent,
+		clockSrc:                 s.clockSrc,
+		trackClosers:             s.trackClosers,
+		eventHandlers:            s.eventHandlers,
+		tracer:                   s.tracer,
+		clonedFrom:               s,
+	}
+	if parent == nil {
+		// The copy of a root Scope is a Container of its own.
+		clone.mu = new(sync.RWMutex)
+		clone.cleanupSeq = new(uint64)
+		clone.builds = new(buildTracker)
+		if s.workers != nil {
+			clone.workers = newWorkerPool(cap(s.workers.slots))
+		}
+	} else {
+		clone.mu = parent.mu
+		clone.cleanupSeq = parent.cleanupSeq
+		clone.builds = parent.builds
+		clone.workers = parent.workers
+	}
+	clone.gh = newGraphHolder(clone)
+	sc.scopes[s] = clone
+
+	for k, ps := range s.providers {
+		cps := make([]*constructorNode, len(ps))
+		for i, p := range ps {
+			cps[i] = sc.ctor(p)
+		}
+		clone.providers[k] = cps
+	}
+	for k, d := range s.decorators {
+		clone.decorators[k] = sc.dcor(d)
+	}
+	for _, n := range s.nodes {
+		clone.nodes = append(clone.nodes, sc.ctor(n))
+	}
+
+	for _, cs := range s.childScopes {
+		clone.childScopes = append(clone.childScopes, sc.cloneScope(cs, clone))
+	}
+	return clone
+}
+
+// cloneGraphs fills in the graphs of the copied Scopes. Nodes keep the
+// order they had in the original graphs.
+func (sc *scopeCloner) cloneGraphs() {
+	for s, clone := range sc.scopes {
+		for _, gn := range s.gh.nodes {
+			w := gn.Wrapped
+			if n, ok := w.(*constructorNode); ok {
+				cn := sc.ctor(n)
+				cn.orders[clone] = n.orders[s]
+				w = cn
+			}
+			// Value groups are shared with the original graph. Their
+			// orders are looked up through clonedFrom.
+			clone.gh.nodes = append(clone.gh.nodes, &graphNode{Wrapped: w})
+		}
+	}
+
+	for n, cn := range sc.dcors {
+		for s, o := range n.orders {
+			if clone, ok := sc.scopes[s]; ok {
+				cn.orders[clone] = o
+			}
+		}
+	}
+}
+
+// scope returns the copy of s, or s itself if it is not being copied.
+func (sc *scopeCloner) scope(s *Scope) *Scope {
+	if clone, ok := sc.scopes[s]; ok {
+		return clone
+	}
+	return s
+}
+
+// ctor returns the copy of n, making it if necessary. Constructors that
+// belong to Scopes that are not being copied are returned as-is.
+func (sc *scopeCloner) ctor(n *constructorNode) *constructorNode {
+	if cn, ok := sc.ctors[n]; ok {
+		return cn
+	}
+	if _, ok := sc.scopes[n.s]; !ok {
+		return n
+	}
+
+	cn := &constructorNode{
+		ctor:           n.ctor,
+		ctype:          n.ctype,
+		location:       n.location,
+		id:             n.id,
+		paramList:      n.paramList,
+		resultList:     n.resultList,
+		orders:         make(map[*Scope]int, len(n.orders)),
+		s:              sc.scopes[n.s],
+		origS:          sc.scope(n.origS),
+		exported:       n.exported,
+		as:             n.as,
+		callback:       n.callback,
+		beforeCallback: n.beforeCallback,
+		transient:      n.transient,
+		perScope:       n.perScope,
+		timeout:        n.timeout,
+		retry:          n.retry,
+		overridden:     n.overridden,
+	}
+	sc.ctors[n] = cn
+	return cn
+}
+
+// dcor returns the copy of n, making it if necessary.
+func (sc *scopeCloner) dcor(n *decoratorNode) *decoratorNode {
+	if cn, ok := sc.dcors[n]; ok {
+		return cn
+	}
+
+	cn := &decoratorNode{
+		dcor:           n.dcor,
+		dtype:          n.dtype,
+		id:             n.id,
+		location:       n.location,
+		params:         n.params,
+		results:        n.results,
+		keys:           n.keys,
+		orders:         make(map[*Scope]int, len(n.orders)),
+		s:              sc.scope(n.s),
+		callback:       n.callback,
+		beforeCallback: n.beforeCallback,
+	}
+	sc.dcors[n] = cn
+	return cn
+}
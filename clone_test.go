@@ -0,0 +1,222 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig_test
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/dig"
+	"go.uber.org/dig/internal/digtest"
+)
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	type A struct{ name string }
+	type B struct{ a *A }
+
+	t.Run("values are not copied", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int
+		c := digtest.New(t)
+		c.RequireProvide(func() *A {
+			calls++
+			return &A{name: "real"}
+		})
+
+		var orig *A
+		c.RequireInvoke(func(a *A) { orig = a })
+
+		clone := c.Clone()
+		require.NoError(t, clone.Invoke(func(a *A) {
+			assert.NotSame(t, orig, a)
+			assert.Equal(t, "real", a.name)
+		}))
+		assert.Equal(t, 2, calls)
+
+		c.RequireInvoke(func(a *A) { assert.Same(t, orig, a) })
+	})
+
+	t.Run("providers are isolated", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() *A { return &A{name: "real"} })
+
+		clone := c.Clone()
+		require.NoError(t, clone.Replace(func() *A { return &A{name: "fake"} }))
+		require.NoError(t, clone.Provide(func(a *A) *B { return &B{a: a} }))
+
+		require.NoError(t, clone.Invoke(func(b *B) {
+			assert.Equal(t, "fake", b.a.name)
+		}))
+		c.RequireInvoke(func(a *A) {
+			assert.Equal(t, "real", a.name)
+		})
+		assert.Error(t, c.Invoke(func(*B) {}))
+
+		c.RequireProvide(func() string { return "added later" })
+		assert.Error(t, clone.Invoke(func(string) {}))
+	})
+
+	t.Run("decorators are copied", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int
+		c := digtest.New(t)
+		c.RequireProvide(func() *A { return &A{name: "real"} })
+		c.RequireDecorate(func(a *A) *A {
+			calls++
+			return &A{name: a.name + " decorated"}
+		})
+		c.RequireInvoke(func(*A) {})
+
+		clone := c.Clone()
+		require.NoError(t, clone.Invoke(func(a *A) {
+			assert.Equal(t, "real decorated", a.name)
+		}))
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("value groups", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() *A { return &A{name: "1"} }, dig.Group("as"))
+
+		type params struct {
+			dig.In
+
+			As []*A `group:"as"`
+		}
+		c.RequireProvide(func(p params) *B { return &B{a: p.As[0]} })
+
+		clone := c.Clone()
+		require.NoError(t, clone.Invoke(func(b *B) {
+			assert.Equal(t, "1", b.a.name)
+		}))
+
+		// The clone keeps track of the value group when looking for
+		// cycles.
+		err := clone.Provide(func(*B) *A { return nil }, dig.Group("as"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "this function introduces a cycle")
+	})
+
+	t.Run("scope", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() *A { return &A{name: "parent"} })
+
+		s := c.Scope("child")
+		s.RequireProvide(func(a *A) *B { return &B{a: a} })
+
+		clone := s.Clone()
+		require.NoError(t, clone.Invoke(func(b *B) {
+			assert.Equal(t, "parent", b.a.name)
+		}))
+
+		other := s.Clone()
+		require.NoError(t, other.Replace(func() *B { return &B{} }))
+		require.NoError(t, other.Invoke(func(b *B) {
+			assert.Nil(t, b.a)
+		}))
+
+		s.RequireInvoke(func(b *B) {
+			assert.Equal(t, "parent", b.a.name)
+		})
+
+		// Constructors provided to the parent later are visible to the
+		// copy.
+		c.RequireProvide(func() string { return "later" })
+		require.NoError(t, clone.Invoke(func(s string) {
+			assert.Equal(t, "later", s)
+		}))
+	})
+
+	t.Run("descendant scopes", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() *A { return &A{name: "root"} }, dig.PerScope())
+		c.Scope("child").RequireProvide(func(a *A) *B { return &B{a: a} })
+
+		var b bytes.Buffer
+		require.NoError(t, dig.Visualize(c.Container, &b))
+		want := b.String()
+
+		clone := c.Clone()
+		b.Reset()
+		require.NoError(t, dig.Visualize(clone, &b))
+		assert.Equal(t, want, b.String())
+	})
+
+	t.Run("cleanups", func(t *testing.T) {
+		t.Parallel()
+
+		var closed []string
+		c := digtest.New(t)
+		c.RequireProvide(func() (*A, func()) {
+			a := &A{}
+			return a, func() { closed = append(closed, "A") }
+		}, dig.Cleanup())
+		c.RequireInvoke(func(*A) {})
+
+		clone := c.Clone()
+		require.NoError(t, clone.Invoke(func(*A) {}))
+		require.NoError(t, clone.Close())
+		assert.Equal(t, []string{"A"}, closed)
+
+		require.NoError(t, c.Close())
+		assert.Equal(t, []string{"A", "A"}, closed)
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() *A { return &A{name: "real"} })
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				clone := c.Clone()
+				assert.NoError(t, clone.Replace(func() *A { return &A{name: "fake"} }))
+				assert.NoError(t, clone.Invoke(func(a *A) {
+					assert.Equal(t, "fake", a.name)
+				}))
+			}()
+			assert.NoError(t, c.Invoke(func(a *A) {
+				assert.Equal(t, "real", a.name)
+			}))
+		}
+		wg.Wait()
+	})
+}
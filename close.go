@@ -0,0 +1,538 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// TrackClosers is an [Option] that makes the container close values that
+// implement [io.Closer] when it is closed with [Container.Close].
+//
+// Values produced by constructors that return a cleanup function are not
+// tracked: the cleanup function is expected to release them.
+func TrackClosers() Option {
+	return trackClosersOption{}
+}
+
+type trackClosersOption struct{}
+
+func (trackClosersOption) String() string {
+	return "TrackClosers()"
+}
+
+func (trackClosersOption) applyOption(c *Container) {
+	c.scope.trackClosers = true
+}
+
+// Close runs the cleanup functions of all values constructed by the
+// Container and all of its Scopes. See [Scope.Close] for details.
+func (c *Container) Close() error {
+	return c.scope.Close()
+}
+
+// Close runs the cleanup functions of all values constructed by this Scope
+// and its descendants, in the reverse order of their construction.
+//
+// Constructors provided with the [Cleanup] option register a cleanup
+// function by returning a func() or a func() error after all their other
+// results, and before the error if they return one.
+//
+//	func NewConnection(cfg *Config) (*Conn, func() error, error) {
+//	  conn, err := dial(cfg)
+//	  if err != nil {
+//	    return nil, nil, err
+//	  }
+//	  return conn, conn.Close, nil
+//	}
+//
+//	c.Provide(NewConnection, dig.Cleanup())
+//
+// Without that option, such functions are provided to the container as
+// values like any other result. If the container was created with
+// [TrackClosers], values that implement [io.Closer] are closed as well.
+//
+// All cleanup functions are run even if some of them fail. The errors
+// returned by them are reported together, and can be inspected with
+// errors.Is and errors.As. Each cleanup function is run at
+// most once: calling Close again only runs the cleanups of values
+// constructed since the previous call.
+//
+// Values that were already constructed are not removed from the Scope, so
+// it should not be used after it was closed.
+func (s *Scope) Close() error {
+	s.mu.RLock()
+	scopes := s.appendSubscopes(nil)
+	s.mu.RUnlock()
+
+	var cleanups []*cleanup
+	for _, s := range scopes {
+		s.valuesMu.Lock()
+		cleanups = append(cleanups, s.cleanups...)
+		s.cleanups = nil
+		s.valuesMu.Unlock()
+	}
+
+	sort.Slice(cleanups, func(i, j int) bool {
+		return cleanups[i].seq > cleanups[j].seq
+	})
+	return newErrCleanupFailed(nil, runCleanups(cleanups))
+}
+
+// cleanup is a function that must be run when the value it was registered
+// for is no longer needed.
+type cleanup struct {
+	// Order in which the function was registered across the whole
+	// Container.
+	seq uint64
+
+	fn func() error
+}
+
+// runCleanups runs the given cleanups in order and returns their errors.
+func runCleanups(cleanups []*cleanup) []error {
+	var errs []error
+	for _, c := range cleanups {
+		if err := c.fn(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// errCleanupFailed is returned when cleanup functions fail. If they were
+// run to roll back a failed Invoke, Reason is the error of the Invoke.
+type errCleanupFailed struct {
+	Reason error   // may be nil
+	Errors []error // inv: len > 0
+}
+
+var _ digError = errCleanupFailed{}
+
+// newErrCleanupFailed reports the errors of the cleanup functions that were
+// run because of reason, if any. It returns reason as is if there are no
+// errors.
+func newErrCleanupFailed(reason error, errs []error) error {
+	if len(errs) == 0 {
+		return reason
+	}
+	return errCleanupFailed{Reason: reason, Errors: errs}
+}
+
+func (e errCleanupFailed) Error() string { return fmt.Sprint(e) }
+
+// Unwrap returns the reason of the cleanup so that the dependency path
+// and root cause of a failed Invoke are still reported.
+func (e errCleanupFailed) Unwrap() error { return e.Reason }
+
+// Is reports whether any of the cleanup functions failed with target.
+func (e errCleanupFailed) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error of the cleanup functions that matches target.
+func (e errCleanupFailed) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e errCleanupFailed) writeMessage(w io.Writer, verb string) {
+	if len(e.Errors) == 1 {
+		io.WriteString(w, "1 cleanup function failed:")
+	} else {
+		fmt.Fprintf(w, "%d cleanup functions failed:", len(e.Errors))
+	}
+
+	writeErrorList(w, verb, e.Errors)
+}
+
+// Format reports the reason of the cleanup first, followed by the errors
+// of the cleanup functions.
+func (e errCleanupFailed) Format(w fmt.State, c rune) {
+	verb := "%v"
+	if w.Flag('+') && c == 'v' {
+		verb = "%+v"
+	}
+
+	if e.Reason != nil {
+		fmt.Fprintf(w, verb, e.Reason)
+		io.WriteString(w, "\n")
+	}
+	e.writeMessage(w, verb)
+}
+
+var (
+	_cleanupType    = reflect.TypeOf(func() {})
+	_errCleanupType = reflect.TypeOf(func() error { return nil })
+	_closerType     = reflect.TypeOf((*io.Closer)(nil)).Elem()
+)
+
+// isCleanupType reports whether t is the type of a cleanup function that
+// may be returned by constructors.
+func isCleanupType(t reflect.Type) bool {
+	return t == _cleanupType || t == _errCleanupType
+}
+
+// newCleanupFunc adapts a cleanup function returned by a constructor. It
+// returns nil if the constructor returned a nil function.
+func newCleanupFunc(v reflect.Value) func() error {
+	if v.IsNil() {
+		return nil
+	}
+	switch fn := v.Interface().(type) {
+	case func():
+		return func() error {
+			fn()
+			return nil
+		}
+	case func() error:
+		return fn
+	}
+	return nil
+}
+
+// closersOf returns the Close methods of the distinct values committed by
+// a constructor call that implement io.Closer.
+func closersOf(sr *stagingContainerWriter) []func() error {
+	var (
+		closers []func() error
+		seen    = make(map[interface{}]struct{})
+	)
+	add := func(v reflect.Value) {
+		if !v.IsValid() || !v.Type().Implements(_closerType) {
+			return
+		}
+		if v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return
+			}
+		}
+
+		c := v.Interface().(io.Closer)
+		// The same value may be provided under multiple types with dig.As.
+		if t := reflect.TypeOf(c); t.Kind() == reflect.Ptr {
+			if _, ok := seen[c]; ok {
+				return
+			}
+			seen[c] = struct{}{}
+		}
+		closers = append(closers, c.Close)
+	}
+
+	for _, k := range sortedKeys(sr.values) {
+		add(sr.values[k])
+	}
+	for _, k := range sortedKeys(sr.groups) {
+		for _, v := range sr.groups[k] {
+			add(v)
+		}
+	}
+	return closers
+}
+
+// sortedKeys returns the keys of m sorted by their string representation,
+// for a deterministic order.
+func sortedKeys[V any](m map[key]V) []key {
+	keys := make([]key, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+	return keys
+}
+
+// addCleanups registers the given functions to be run when s is closed,
+// in reverse order.
+func (s *Scope) addCleanups(fns []func() error) []*cleanup {
+	if len(fns) == 0 {
+		return nil
+	}
+
+	cleanups := make([]*cleanup, len(fns))
+	for i, fn := range fns {
+		cleanups[i] = &cleanup{
+			seq: atomic.AddUint64(s.cleanupSeq, 1),
+			fn:  fn,
+		}
+	}
+
+	s.valuesMu.Lock()
+	s.cleanups = append(s.cleanups, cleanups...)
+	s.valuesMu.Unlock()
+	return cleanups
+}
+
+// groupEntry identifies a single value in a value group of a Scope.
+type groupEntry struct {
+	key   key
+	index int
+}
+
+// commitRecord describes the values that a constructor call committed to
+// a Scope, and the cleanup functions registered for them.
+type commitRecord struct {
+	// Whether values were committed as per-scope values.
+	scoped bool
+
+	values   []key
+	groups   []groupEntry
+	cleanups []*cleanup
+}
+
+// detachCommit removes the values described by rec from s and returns the
+// cleanup functions that were registered for them in the order they must
+// be run, unless s was closed in the meantime.
+func (s *Scope) detachCommit(rec commitRecord) []*cleanup {
+	s.valuesMu.Lock()
+	defer s.valuesMu.Unlock()
+
+	values := s.values
+	if rec.scoped {
+		values = s.scopedValues
+	}
+	for _, k := range rec.values {
+		delete(values, k)
+	}
+	for _, g := range rec.groups {
+		// Leave a tombstone rather than shifting the group around so
+		// that the other records stay valid.
+		s.groups[g.key][g.index] = reflect.Value{}
+	}
+
+	if len(rec.cleanups) == 0 {
+		return nil
+	}
+
+	remove := make(map[*cleanup]struct{}, len(rec.cleanups))
+	for _, c := range rec.cleanups {
+		remove[c] = struct{}{}
+	}
+
+	var pending []*cleanup
+	kept := s.cleanups[:0]
+	for _, c := range s.cleanups {
+		if _, ok := remove[c]; ok {
+			pending = append(pending, c)
+		} else {
+			kept = append(kept, c)
+		}
+	}
+	s.cleanups = kept
+
+	for i, j := 0, len(pending)-1; i < j; i, j = i+1, j-1 {
+		pending[i], pending[j] = pending[j], pending[i]
+	}
+	return pending
+}
+
+// undoDecorate removes the values produced by a decorator for the given
+// keys from s.
+func (s *Scope) undoDecorate(keys []key) {
+	s.valuesMu.Lock()
+	defer s.valuesMu.Unlock()
+
+	for _, k := range keys {
+		if k.group != "" {
+			// Decorated value groups are stored as a whole slice.
+			delete(s.decoratedGroups, key{group: k.group, t: reflect.SliceOf(k.t)})
+		} else {
+			delete(s.decoratedValues, k)
+		}
+	}
+}
+
+// buildTracker keeps track of the builds running in a Container.
+type buildTracker struct {
+	mu      sync.Mutex
+	running map[*buildLog]struct{}
+}
+
+// start returns the log of a new build and tracks it until it's finished.
+//
+// Builds that run at the same time may observe each other's values, so
+// they are marked as overlapping.
+func (t *buildTracker) start() *buildLog {
+	l := &buildLog{tracker: t}
+	if t == nil {
+		return l
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for other := range t.running {
+		other.overlap()
+		l.overlapped = true
+	}
+	if t.running == nil {
+		t.running = make(map[*buildLog]struct{})
+	}
+	t.running[l] = struct{}{}
+	return l
+}
+
+// stop stops tracking the given build.
+func (t *buildTracker) stop(l *buildLog) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.running, l)
+}
+
+// buildLog records the changes made to a container while building the
+// dependencies of a single Invoke, so that they can be undone if the
+// Invoke fails.
+type buildLog struct {
+	mu sync.Mutex
+
+	// Functions that undo each change, in the order the changes were
+	// made. They detach the change from the container and return the
+	// cleanup functions that must be run for it.
+	undo []func() []*cleanup
+
+	// Whether any of the changes registered a cleanup function.
+	hasCleanups bool
+
+	// Whether the build is over. Nothing is recorded after that.
+	done bool
+
+	// Whether another build ran at the same time as this one. It may be
+	// using the changes, so they can't be undone.
+	overlapped bool
+
+	// Tracker of the builds running in the Container, if any.
+	tracker *buildTracker
+}
+
+// record adds a change to the log.
+func (l *buildLog) record(undo func() []*cleanup, hasCleanups bool) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.done {
+		return
+	}
+	l.undo = append(l.undo, undo)
+	l.hasCleanups = l.hasCleanups || hasCleanups
+}
+
+// overlap records that another build ran at the same time as this one.
+func (l *buildLog) overlap() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.overlapped = true
+}
+
+// finish marks the build as over and drops the recorded changes, which
+// can't be rolled back anymore.
+func (l *buildLog) finish() {
+	if l == nil {
+		return
+	}
+
+	l.tracker.stop(l)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.undo, l.hasCleanups, l.done = nil, false, true
+}
+
+// finished reports whether the build is over.
+func (l *buildLog) finished() bool {
+	if l == nil {
+		return false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.done
+}
+
+// rollback undoes all recorded changes in reverse order if any of them
+// registered a cleanup function. Everything built alongside such values
+// may hold on to them, so none of it can be kept once they're cleaned up.
+// It returns reason, the error that the build failed with, along with the
+// errors of the cleanup functions.
+//
+// Changes that did not register cleanup functions are kept otherwise, as
+// dig always did. Changes are kept as well if another build ran at the
+// same time, since it may be using them. Their cleanup functions run when
+// the container is closed.
+func (l *buildLog) rollback(reason error) error {
+	if l == nil {
+		return reason
+	}
+	return newErrCleanupFailed(reason, runCleanups(l.detach()))
+}
+
+// detach removes the recorded changes from the container for rollback
+// and returns the cleanup functions that must be run for them.
+func (l *buildLog) detach() []*cleanup {
+	// No other build may start and observe the changes while they're
+	// being removed.
+	if t := l.tracker; t != nil {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+	}
+
+	l.mu.Lock()
+	undo, hasCleanups, overlapped := l.undo, l.hasCleanups, l.overlapped
+	l.undo, l.hasCleanups = nil, false
+	l.mu.Unlock()
+
+	if !hasCleanups || overlapped {
+		return nil
+	}
+
+	var cleanups []*cleanup
+	for i := len(undo) - 1; i >= 0; i-- {
+		cleanups = append(cleanups, undo[i]()...)
+	}
+	return cleanups
+}
@@ -0,0 +1,457 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/dig"
+	"go.uber.org/dig/internal/digtest"
+)
+
+type closer struct {
+	name   string
+	closed *[]string
+	err    error
+}
+
+func (c *closer) Close() error {
+	*c.closed = append(*c.closed, c.name)
+	return c.err
+}
+
+func TestClose(t *testing.T) {
+	t.Parallel()
+
+	type A struct{}
+	type B struct{}
+	type C struct{}
+
+	t.Run("cleanups run in reverse order", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		c := digtest.New(t)
+		c.RequireProvide(func() (*A, func()) {
+			return &A{}, func() { calls = append(calls, "a") }
+		}, dig.Cleanup())
+		c.RequireProvide(func(*A) (*B, func() error, error) {
+			return &B{}, func() error {
+				calls = append(calls, "b")
+				return nil
+			}, nil
+		}, dig.Cleanup())
+		c.RequireProvide(func(*B) (*C, func()) {
+			return &C{}, func() { calls = append(calls, "c") }
+		}, dig.Cleanup())
+		c.RequireInvoke(func(*C) {})
+
+		require.NoError(t, c.Close())
+		assert.Equal(t, []string{"c", "b", "a"}, calls)
+
+		require.NoError(t, c.Close())
+		assert.Len(t, calls, 3, "cleanups must run only once")
+	})
+
+	t.Run("errors are joined", func(t *testing.T) {
+		t.Parallel()
+
+		errA := errors.New("a failed")
+		errB := errors.New("b failed")
+
+		c := digtest.New(t)
+		c.RequireProvide(func() (*A, func() error) {
+			return &A{}, func() error { return errA }
+		}, dig.Cleanup())
+		c.RequireProvide(func() (*B, func() error) {
+			return &B{}, func() error { return errB }
+		}, dig.Cleanup())
+		c.RequireInvoke(func(*A, *B) {})
+
+		err := c.Close()
+		assert.ErrorIs(t, err, errA)
+		assert.ErrorIs(t, err, errB)
+	})
+
+	t.Run("nil cleanup", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() (*A, func()) { return &A{}, nil }, dig.Cleanup())
+		c.RequireInvoke(func(*A) {})
+		assert.NoError(t, c.Close())
+	})
+
+	t.Run("lone func is provided", func(t *testing.T) {
+		t.Parallel()
+
+		var called bool
+		c := digtest.New(t)
+		c.RequireProvide(func() func() {
+			return func() { called = true }
+		})
+		c.RequireInvoke(func(f func()) { f() })
+		assert.True(t, called)
+
+		require.NoError(t, c.Close())
+	})
+
+	t.Run("cleanup is not provided", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() (*A, func()) { return &A{}, func() {} }, dig.Cleanup())
+
+		err := c.Invoke(func(func()) {})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing type: func()")
+	})
+
+	t.Run("trailing func is provided without Cleanup", func(t *testing.T) {
+		t.Parallel()
+
+		var called bool
+		c := digtest.New(t)
+		c.RequireProvide(func() (*A, func()) {
+			return &A{}, func() { called = true }
+		})
+		c.RequireInvoke(func(_ *A, f func()) { f() })
+		assert.True(t, called)
+
+		called = false
+		require.NoError(t, c.Close())
+		assert.False(t, called, "func must not be run as a cleanup")
+	})
+
+	t.Run("Cleanup without a cleanup function", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		err := c.Provide(func() *A { return &A{} }, dig.Cleanup())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot use dig.Cleanup() with func() *dig_test.A")
+
+		err = c.Provide(func() func() { return func() {} }, dig.Cleanup())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "it must return a func() or func() error after its other results")
+	})
+
+	t.Run("scopes", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		c := digtest.New(t)
+		c.RequireProvide(func() (*A, func()) {
+			return &A{}, func() { calls = append(calls, "a") }
+		}, dig.Cleanup())
+
+		s := c.Scope("child")
+		s.RequireProvide(func(*A) (*B, func()) {
+			return &B{}, func() { calls = append(calls, "b") }
+		}, dig.Cleanup())
+		gc := s.Scope("grandchild")
+		gc.RequireProvide(func(*B) (*C, func()) {
+			return &C{}, func() { calls = append(calls, "c") }
+		}, dig.Cleanup())
+		gc.RequireInvoke(func(*C) {})
+
+		require.NoError(t, s.Close())
+		assert.Equal(t, []string{"c", "b"}, calls)
+
+		require.NoError(t, c.Close())
+		assert.Equal(t, []string{"c", "b", "a"}, calls)
+	})
+
+	t.Run("TrackClosers", func(t *testing.T) {
+		t.Parallel()
+
+		type result struct {
+			dig.Out
+
+			Closers []*closer `group:"closers,flatten"`
+		}
+
+		var closed []string
+		c := digtest.New(t, dig.TrackClosers())
+		c.RequireProvide(func() (*closer, io.Closer) {
+			// The same value provided twice must only be closed once.
+			c := &closer{name: "single", closed: &closed}
+			return c, c
+		})
+		c.RequireProvide(func() result {
+			return result{Closers: []*closer{
+				{name: "group", closed: &closed},
+			}}
+		})
+		c.RequireProvide(func() (*A, *closer, func()) {
+			return &A{}, &closer{name: "untracked", closed: &closed}, func() {
+				closed = append(closed, "cleanup")
+			}
+		}, dig.Name("untracked"), dig.Cleanup())
+
+		c.RequireInvoke(func(struct {
+			dig.In
+
+			Single    *closer
+			Closer    io.Closer
+			Group     []*closer `group:"closers"`
+			Untracked *closer   `name:"untracked"`
+		}) {
+		})
+
+		require.NoError(t, c.Close())
+		assert.ElementsMatch(t, []string{"single", "group", "cleanup"}, closed)
+	})
+
+	t.Run("closers are not tracked by default", func(t *testing.T) {
+		t.Parallel()
+
+		var closed []string
+		c := digtest.New(t)
+		c.RequireProvide(func() *closer {
+			return &closer{name: "single", closed: &closed}
+		})
+		c.RequireInvoke(func(*closer) {})
+
+		require.NoError(t, c.Close())
+		assert.Empty(t, closed)
+	})
+}
+
+func TestCloseFailedInvoke(t *testing.T) {
+	t.Parallel()
+
+	type A struct{}
+	type B struct{}
+	type C struct{}
+
+	t.Run("values built by the Invoke are cleaned up", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			calls  []string
+			aCalls int
+			fail   = true
+		)
+		c := digtest.New(t)
+		c.RequireProvide(func() (*A, func()) {
+			aCalls++
+			return &A{}, func() { calls = append(calls, "a") }
+		}, dig.Cleanup())
+		c.RequireProvide(func(*A) *B { return &B{} })
+		c.RequireProvide(func(*B) (*C, error) {
+			if fail {
+				return nil, errors.New("great sadness")
+			}
+			return &C{}, nil
+		})
+
+		err := c.Invoke(func(*C) {})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "great sadness")
+		assert.Equal(t, []string{"a"}, calls)
+
+		fail = false
+		c.RequireInvoke(func(*C) {})
+		assert.Equal(t, 2, aCalls, "*A must be constructed again")
+
+		require.NoError(t, c.Close())
+		assert.Equal(t, []string{"a", "a"}, calls)
+	})
+
+	t.Run("values used by a concurrent Invoke are kept", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			closed  int
+			aCalls  int
+			built   = make(chan struct{})
+			using   = make(chan struct{})
+			release = make(chan struct{})
+			done    = make(chan error)
+		)
+		c := digtest.New(t)
+		c.RequireProvide(func() (*A, func()) {
+			aCalls++
+			close(built)
+			return &A{}, func() { closed++ }
+		}, dig.Cleanup())
+		c.RequireProvide(func(*A) (*B, error) {
+			// Fail only once the other Invoke got hold of *A.
+			<-using
+			return nil, errors.New("great sadness")
+		})
+
+		go func() {
+			<-built
+			done <- c.Invoke(func(*A) {
+				close(using)
+				<-release
+			})
+		}()
+
+		err := c.Invoke(func(*B) {})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "great sadness")
+		assert.Zero(t, closed, "*A must not be cleaned up while in use")
+
+		close(release)
+		require.NoError(t, <-done)
+
+		c.RequireInvoke(func(*A) {})
+		assert.Equal(t, 1, aCalls, "*A must be kept")
+
+		require.NoError(t, c.Close())
+		assert.Equal(t, 1, closed)
+	})
+
+	t.Run("cleanup errors are reported", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() (*A, func() error) {
+			return &A{}, func() error { return errors.New("cleanup failed") }
+		}, dig.Cleanup())
+		c.RequireProvide(func() (*B, error) {
+			return nil, errors.New("great sadness")
+		})
+
+		err := c.Invoke(func(*A, *B) {})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "great sadness")
+		assert.Contains(t, err.Error(), "cleanup failed")
+	})
+
+	t.Run("values built earlier are kept", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		c := digtest.New(t)
+		c.RequireProvide(func() (*A, func()) {
+			return &A{}, func() { calls = append(calls, "a") }
+		}, dig.Cleanup())
+		c.RequireProvide(func() (*B, error) {
+			return nil, errors.New("great sadness")
+		})
+		c.RequireInvoke(func(*A) {})
+
+		require.Error(t, c.Invoke(func(*A, *B) {}))
+		assert.Empty(t, calls)
+
+		require.NoError(t, c.Close())
+		assert.Equal(t, []string{"a"}, calls)
+	})
+
+	t.Run("values without cleanups are kept", func(t *testing.T) {
+		t.Parallel()
+
+		var aCalls int
+		c := digtest.New(t)
+		c.RequireProvide(func() *A {
+			aCalls++
+			return &A{}
+		})
+		c.RequireProvide(func(*A) (*B, error) {
+			return nil, errors.New("great sadness")
+		})
+
+		require.Error(t, c.Invoke(func(*B) {}))
+		c.RequireInvoke(func(*A) {})
+		assert.Equal(t, 1, aCalls)
+	})
+
+	t.Run("value groups and decorators", func(t *testing.T) {
+		t.Parallel()
+
+		type params struct {
+			dig.In
+
+			Values []int `group:"values"`
+		}
+
+		type result struct {
+			dig.Out
+
+			Values []int `group:"values"`
+		}
+
+		var (
+			calls      []string
+			decorated  int
+			fail       = true
+			groupCalls int
+		)
+		c := digtest.New(t)
+		c.RequireProvide(func() (int, func()) {
+			groupCalls++
+			return 1, func() { calls = append(calls, "group") }
+		}, dig.Group("values"), dig.Cleanup())
+		c.RequireProvide(func() int { return 2 }, dig.Group("values"))
+		c.RequireDecorate(func(p params) result {
+			decorated++
+			return result{Values: append(p.Values, 3)}
+		})
+		c.RequireProvide(func() (*A, error) {
+			if fail {
+				return nil, errors.New("great sadness")
+			}
+			return &A{}, nil
+		})
+
+		require.Error(t, c.Invoke(func(params, *A) {}))
+		assert.Equal(t, []string{"group"}, calls)
+
+		fail = false
+		c.RequireInvoke(func(p params, _ *A) {
+			assert.ElementsMatch(t, []int{1, 2, 3}, p.Values)
+		})
+		assert.Equal(t, 2, groupCalls)
+		assert.Equal(t, 2, decorated)
+	})
+
+	t.Run("Resolve", func(t *testing.T) {
+		t.Parallel()
+
+		type params struct {
+			dig.In
+
+			A *A
+			B *B
+		}
+
+		var calls []string
+		c := digtest.New(t)
+		c.RequireProvide(func() (*A, func()) {
+			return &A{}, func() { calls = append(calls, "a") }
+		}, dig.Cleanup())
+		c.RequireProvide(func() (*B, error) {
+			return nil, errors.New("great sadness")
+		})
+
+		_, err := dig.Resolve[params](c)
+		require.Error(t, err)
+		assert.Equal(t, []string{"a"}, calls)
+	})
+}
@@ -0,0 +1,223 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/dig"
+	"go.uber.org/dig/internal/digtest"
+)
+
+func TestCollectErrors(t *testing.T) {
+	t.Parallel()
+
+	type A struct{}
+	type B struct{}
+	type C struct{}
+	type D struct{}
+
+	errA := errors.New("bad A")
+	errB := errors.New("bad B")
+
+	t.Run("independent failures", func(t *testing.T) {
+		t.Parallel()
+
+		var calledB, calledC atomic.Bool
+		c := digtest.New(t)
+		c.RequireProvide(func() (*A, error) { return nil, errA })
+		c.RequireProvide(func() (*B, error) {
+			calledB.Store(true)
+			return nil, errB
+		})
+		c.RequireProvide(func() *C {
+			calledC.Store(true)
+			return &C{}
+		})
+
+		err := c.Invoke(func(*A, *B, *C) {})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errA)
+		assert.NotErrorIs(t, err, errB, "must stop at the first failure by default")
+		assert.False(t, calledB.Load())
+
+		err = c.Invoke(func(*A, *B, *C) {}, dig.CollectErrors())
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errA)
+		assert.ErrorIs(t, err, errB)
+		assert.True(t, calledC.Load(), "independent constructors must be called")
+		assert.Contains(t, err.Error(), "2 dependencies failed to build:")
+		assert.Contains(t, err.Error(), "\n[1] failed to build *dig_test.A")
+		assert.Contains(t, err.Error(), "\n[2] failed to build *dig_test.B")
+	})
+
+	t.Run("failed constructors are called once", func(t *testing.T) {
+		t.Parallel()
+
+		var calls, calledD atomic.Int32
+		c := digtest.New(t)
+		c.RequireProvide(func() (*A, error) {
+			calls.Add(1)
+			return nil, errA
+		})
+		c.RequireProvide(func(*A) *B { return &B{} })
+		c.RequireProvide(func(*A) *C { return &C{} })
+		c.RequireProvide(func(*B) *D {
+			calledD.Add(1)
+			return &D{}
+		})
+
+		err := c.Invoke(func(*C, *D) {}, dig.CollectErrors())
+		require.Error(t, err)
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Zero(t, calledD.Load(), "dependents of failed constructors must be skipped")
+
+		ctorA, ok := dig.FailedConstructor(err)
+		require.True(t, ok)
+
+		path := dig.DependencyPath(err)
+		require.Len(t, path, 3)
+		assert.Equal(t, dig.Key{Type: reflect.TypeOf(&C{})}, path[0].Key)
+		assert.Equal(t, ctorA, path[2].Location)
+
+		tree := dig.ErrorTree(err)
+		lines := strings.Split(tree, "\n")
+		require.Len(t, lines, 8, "tree:\n%v", tree)
+		assert.Equal(t, path[0].Location.String(), lines[0])
+		assert.Equal(t, "├─ *dig_test.C: "+path[1].Location.String(), lines[1])
+		assert.Equal(t, "│  └─ *dig_test.A: "+ctorA.String(), lines[2])
+		assert.Equal(t, "│     └─ bad A", lines[3])
+		assert.True(t, strings.HasPrefix(lines[4], "└─ *dig_test.D: "), lines[4])
+		assert.True(t, strings.HasPrefix(lines[5], "   └─ *dig_test.B: "), lines[5])
+		assert.Equal(t, "      └─ *dig_test.A: "+ctorA.String(), lines[6])
+		assert.Equal(t, "         └─ bad A", lines[7])
+	})
+
+	t.Run("missing types", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func(*A) *B { return &B{} })
+		c.RequireProvide(func(*C) *D { return &D{} })
+
+		err := c.Invoke(func(*B, *D) {}, dig.CollectErrors())
+		require.Error(t, err)
+		assert.Equal(t, []dig.Key{
+			{Type: reflect.TypeOf(&A{})},
+			{Type: reflect.TypeOf(&C{})},
+		}, dig.MissingTypes(err))
+	})
+
+	t.Run("parameter objects and groups", func(t *testing.T) {
+		t.Parallel()
+
+		type out struct {
+			dig.Out
+
+			A *A `group:"as"`
+		}
+		type in struct {
+			dig.In
+
+			As []*A `group:"as"`
+			B  *B
+		}
+
+		c := digtest.New(t)
+		for i := 0; i < 2; i++ {
+			err := fmt.Errorf("bad A %d", i)
+			c.RequireProvide(func() (out, error) { return out{}, err })
+		}
+		c.RequireProvide(func() (*B, error) { return nil, errB })
+
+		err := c.Invoke(func(in) {}, dig.CollectErrors())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "3 dependencies failed to build:")
+		assert.Contains(t, err.Error(), "bad A 0")
+		assert.Contains(t, err.Error(), "bad A 1")
+		assert.ErrorIs(t, err, errB)
+	})
+
+	t.Run("parallel construction", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t, dig.ParallelConstruction(4))
+		c.RequireProvide(func() (*A, error) { return nil, errA })
+		c.RequireProvide(func() (*B, error) { return nil, errB })
+		c.RequireProvide(func() *C { return &C{} })
+
+		err := c.Invoke(func(*A, *B, *C) {}, dig.CollectErrors())
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errA)
+		assert.ErrorIs(t, err, errB)
+	})
+
+	t.Run("single failure", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() (*A, error) { return nil, errA })
+		c.RequireProvide(func() *B { return &B{} })
+
+		invoke := func(*A, *B) {}
+		want := c.Invoke(invoke)
+		got := c.Invoke(invoke, dig.CollectErrors())
+		require.Error(t, got)
+		assert.Equal(t, want.Error(), got.Error(),
+			"a single failure must be reported the same way")
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() (*A, error) { return nil, errA })
+		c.RequireProvide(func() (*B, error) { return nil, errB })
+
+		err := c.Invoke(func(*A, *B) {}, dig.CollectErrors())
+		require.Error(t, err)
+
+		b, merr := json.Marshal(err)
+		require.NoError(t, merr)
+
+		var je testJSONError
+		require.NoError(t, json.Unmarshal(b, &je))
+		require.NotNil(t, je.Cause)
+		assert.Equal(t, "dependenciesFailed", je.Cause.Kind)
+		require.Len(t, je.Cause.Errors, 2)
+		assert.Equal(t, &testJSONKey{Type: "*dig_test.A"}, je.Cause.Errors[0].Key)
+		assert.Equal(t, &testJSONKey{Type: "*dig_test.B"}, je.Cause.Errors[1].Key)
+	})
+
+	t.Run("String", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, "CollectErrors()", fmt.Sprint(dig.CollectErrors()))
+	})
+}
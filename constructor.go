@@ -21,8 +21,12 @@
 package dig
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"sync"
+	"time"
 
 	"go.uber.org/dig/internal/digerror"
 	"go.uber.org/dig/internal/digreflect"
@@ -45,8 +49,9 @@ type constructorNode struct {
 	// id uniquely identifies the constructor that produces a node.
 	id dot.CtorID
 
-	// Whether the constructor owned by this node was already called.
-	called bool
+	// Tracks whether the constructor owned by this node was already
+	// called, and synchronizes concurrent calls to it.
+	callGate
 
 	// Type information about constructor parameters.
 	paramList paramList
@@ -64,11 +69,43 @@ type constructorNode struct {
 	// This is different from s if and only if the constructor was Provided with ExportOption.
 	origS *Scope
 
+	// Whether the constructor was provided with the Export option.
+	exported bool
+
+	// Interfaces the results of the constructor are provided as.
+	as []reflect.Type
+
 	// Callback for this provided function, if there is one.
 	callback Callback
 
 	// BeforeCallback for this provided function, if there is one.
 	beforeCallback BeforeCallback
+
+	// Whether this constructor is called for every dependent instead of
+	// once. Values produced by transient constructors are never stored.
+	transient bool
+
+	// Whether this constructor is called once for every Scope that
+	// requests its values, instead of once for s.
+	perScope bool
+
+	// For per-scope constructors, tracks the calls made for each Scope.
+	// Guarded by scopeGatesMu.
+	scopeGates   map[*Scope]*callGate
+	scopeGatesMu sync.Mutex
+
+	// Maximum duration of a call to the constructor, if any.
+	timeout time.Duration
+
+	// How the constructor is called again after it fails, if it is.
+	retry *RetryPolicy
+
+	// Statistics on the calls made to the constructor.
+	stats callStats
+
+	// Results of the constructor that another constructor was provided to
+	// override. Their values are discarded.
+	overridden map[key]struct{}
 }
 
 type constructorOptions struct {
@@ -80,6 +117,12 @@ type constructorOptions struct {
 	Location       *digreflect.Func
 	Callback       Callback
 	BeforeCallback BeforeCallback
+	Exported       bool
+	Transient      bool
+	PerScope       bool
+	Timeout        time.Duration
+	Retry          *RetryPolicy
+	Cleanup        bool
 }
 
 func newConstructorNode(ctor interface{}, s *Scope, origS *Scope, opts constructorOptions) (*constructorNode, error) {
@@ -95,14 +138,24 @@ func newConstructorNode(ctor interface{}, s *Scope, origS *Scope, opts construct
 	results, err := newResultList(
 		ctype,
 		resultOptions{
-			Name:  opts.ResultName,
-			Group: opts.ResultGroup,
-			As:    opts.ResultAs,
+			Name:    opts.ResultName,
+			Group:   opts.ResultGroup,
+			As:      opts.ResultAs,
+			Cleanup: opts.Cleanup,
 		},
 	)
 	if err != nil {
 		return nil, err
 	}
+	if opts.Cleanup && results.cleanupIndex < 0 {
+		return nil, newErrInvalidInput(fmt.Sprintf(
+			"cannot use dig.Cleanup() with %v: it must return a func() or func() error after its other results", ctype), nil)
+	}
+
+	var as []reflect.Type
+	for _, i := range opts.ResultAs {
+		as = append(as, reflect.TypeOf(i).Elem())
+	}
 
 	location := opts.Location
 	if location == nil {
@@ -119,8 +172,14 @@ func newConstructorNode(ctor interface{}, s *Scope, origS *Scope, opts construct
 		orders:         make(map[*Scope]int),
 		s:              s,
 		origS:          origS,
+		exported:       opts.Exported,
+		as:             as,
 		callback:       opts.Callback,
 		beforeCallback: opts.BeforeCallback,
+		transient:      opts.Transient,
+		perScope:       opts.PerScope,
+		timeout:        opts.Timeout,
+		retry:          opts.Retry,
 	}
 	s.newGraphNode(n, n.orders)
 	return n, nil
@@ -133,6 +192,8 @@ func (n *constructorNode) ID() dot.CtorID             { return n.id }
 func (n *constructorNode) CType() reflect.Type        { return n.ctype }
 func (n *constructorNode) Order(s *Scope) int         { return n.orders[s] }
 func (n *constructorNode) OrigScope() *Scope          { return n.origS }
+func (n *constructorNode) Transient() bool            { return n.transient }
+func (n *constructorNode) PerScope() bool             { return n.perScope }
 
 // CopyOrder copies the order for the given parent scope to the given child scope.
 func (n *constructorNode) CopyOrder(parent, child *Scope) {
@@ -145,32 +206,242 @@ func (n *constructorNode) String() string {
 
 // Call calls this constructor if it hasn't already been called and
 // injects any values produced by it into the provided container.
-func (n *constructorNode) Call(c containerStore) (err error) {
-	if n.called {
+//
+// If the constructor is already being called by another goroutine, Call
+// waits for it to finish and reports its outcome.
+//
+// Transient constructors are called every time and their values are
+// discarded. Use Produce to get hold of them.
+func (n *constructorNode) Call(c containerStore, bs *buildState) (err error) {
+	if n.transient {
+		_, err := n.Produce(c, bs)
+		return err
+	}
+
+	// Commit the result to the original container that this constructor
+	// was supplied to. The provided constructor is only used for a view of
+	// the rest of the graph to instantiate the dependencies of this
+	// container.
+	//
+	// Per-scope constructors commit to the container they are called with
+	// instead.
+	target, gate := n.s, &n.callGate
+	if n.perScope {
+		target = c.asScope()
+		gate = n.scopeGate(target)
+	}
+	if err, ok := bs.failed(gate); ok {
+		return err
+	}
+
+	call, owner := gate.enter()
+	if call == nil {
 		return nil
 	}
+	if !owner {
+		// A Lazy used during construction, or a call into the
+		// container made by the constructor, may depend on the
+		// constructor that is being called. Waiting would never end.
+		if bs.has(n) || call.reentrant() {
+			return bs.cycleError(n, target)
+		}
+		span := c.asScope().startSpan(bs, SpanWait, n.location)
+		err := call.wait()
+		span.end(err)
+		return err
+	}
+	defer func() { gate.exit(call, err, recover()) }()
+
+	receiver, cleanups, err := n.call(c, bs)
+	if err != nil {
+		bs.fail(gate, err)
+		return err
+	}
 
+	receiver.discard(n.overridden)
+	rec := receiver.Commit(target, n.perScope)
+	rec.cleanups = target.addCleanups(cleanups)
+
+	bs.record(func() []*cleanup {
+		cleanups := target.detachCommit(rec)
+		gate.reset()
+		return cleanups
+	}, len(rec.cleanups) > 0)
+	return nil
+}
+
+// scopeGate returns the gate that tracks the calls made to this per-scope
+// constructor for the given Scope.
+func (n *constructorNode) scopeGate(s *Scope) *callGate {
+	n.scopeGatesMu.Lock()
+	defer n.scopeGatesMu.Unlock()
+
+	if n.scopeGates == nil {
+		n.scopeGates = make(map[*Scope]*callGate)
+	}
+	g, ok := n.scopeGates[s]
+	if !ok {
+		g = new(callGate)
+		n.scopeGates[s] = g
+	}
+	return g
+}
+
+// override records that the results of this constructor with the given
+// keys were overridden. Value groups are never overridden.
+//
+// The set of overridden results is copied rather than modified in place
+// since copies of the constructor made by Clone share it.
+func (n *constructorNode) override(keys map[key]struct{}) {
+	overridden := make(map[key]struct{}, len(n.overridden)+len(keys))
+	for k := range n.overridden {
+		overridden[k] = struct{}{}
+	}
+	for k := range keys {
+		if k.group == "" {
+			overridden[k] = struct{}{}
+		}
+	}
+	n.overridden = overridden
+}
+
+// instantiated reports whether this constructor was called, or is being
+// called, for any Scope. Transient constructors are never considered
+// instantiated since their values are not kept.
+func (n *constructorNode) instantiated() bool {
+	if n.callGate.used() {
+		return true
+	}
+
+	n.scopeGatesMu.Lock()
+	defer n.scopeGatesMu.Unlock()
+	for _, g := range n.scopeGates {
+		if g.used() {
+			return true
+		}
+	}
+	return false
+}
+
+// Produce calls this constructor and returns the values it produced
+// without storing them in the container. This is how transient
+// constructors provide a new value to each of their dependents.
+func (n *constructorNode) Produce(c containerStore, bs *buildState) (*stagingContainerWriter, error) {
+	if bs.has(n) {
+		return nil, bs.cycleError(n, n.s)
+	}
+
+	receiver, cleanups, err := n.call(c, bs)
+	if err != nil {
+		return nil, err
+	}
+
+	rec := commitRecord{cleanups: n.s.addCleanups(cleanups)}
+	bs.record(func() []*cleanup {
+		return n.s.detachCommit(rec)
+	}, len(rec.cleanups) > 0)
+	return receiver, nil
+}
+
+// call builds the dependencies of this constructor and calls it. The
+// values it produced are staged in the returned writer, along with the
+// functions that clean them up.
+func (n *constructorNode) call(c containerStore, bs *buildState) (receiver *stagingContainerWriter, cleanups []func() error, err error) {
+	callStart := c.clock().Now()
+	span := c.asScope().startSpan(bs, SpanConstructor, n.location)
+	defer func() { span.end(err) }()
+
+	bs = bs.push(n).within(span)
 	if err := shallowCheckDependencies(c, n.paramList); err != nil {
-		return errMissingDependencies{
+		return nil, nil, errMissingDependencies{
 			Func:   n.location,
 			Reason: err,
 		}
 	}
 
-	args, err := n.paramList.BuildList(c)
+	// Dependencies are built with the context of the caller. They may be
+	// shared, so they must not be tied to the timeout of this constructor.
+	ctx := bs.context()
+	args, err := n.paramList.BuildList(c, bs)
 	if err != nil {
-		return errArgumentsFailed{
+		return nil, nil, errArgumentsFailed{
 			Func:   n.location,
 			Reason: err,
 		}
 	}
+	if err := bs.context().Err(); err != nil {
+		return nil, nil, n.canceledError(ctx, err)
+	}
+
+	// The timeout bounds the wait for all the attempts together, including
+	// the backoff between them.
+	waitCtx := ctx
+	if n.timeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, n.timeout)
+		defer cancel()
+	}
+
+	// Errors of the attempts that were retried.
+	var attempts []error
+	for attempt, start := 1, callStart; ; attempt++ {
+		receiver, cleanups, err = n.callFunc(ctx, waitCtx, c, bs, args, start, attempt)
+		if err == nil {
+			return receiver, cleanups, nil
+		}
+		if !n.retry.retries(attempt, err) {
+			break
+		}
+		attempts = append(attempts, errors.Unwrap(err))
+
+		start = c.clock().Now()
+		if werr := sleep(waitCtx, c.clock(), n.retry.backoff(attempt)); werr != nil {
+			err = n.canceledError(ctx, werr)
+			break
+		}
+	}
+	if len(attempts) > 0 {
+		err = errRetriesFailed{Attempts: attempts, Reason: err}
+	}
+	return nil, nil, err
+}
 
+// callFunc calls the constructor once with the given arguments. ctx is
+// the context of the caller of the constructor, waitCtx the context that
+// bounds how long the caller waits for it, and callStart the time at which
+// the call started, including building its dependencies.
+func (n *constructorNode) callFunc(
+	ctx context.Context,
+	waitCtx context.Context,
+	c containerStore,
+	bs *buildState,
+	args []reflect.Value,
+	callStart time.Time,
+	attempt int,
+) (receiver *stagingContainerWriter, cleanups []func() error, err error) {
 	if n.beforeCallback != nil {
 		n.beforeCallback(BeforeCallbackInfo{
-			Name: fmt.Sprintf("%v.%v", n.location.Package, n.location.Name),
+			Name:    fmt.Sprintf("%v.%v", n.location.Package, n.location.Name),
+			Attempt: attempt,
 		})
 	}
 
+	if s := c.asScope(); s.emitting() {
+		loc, outputs := newLocation(n.location), newOutputs(n.resultList)
+		s.emit(ConstructorStart{Location: loc, Scope: s.name, Outputs: outputs})
+
+		start := c.clock().Now()
+		defer func() {
+			s.emit(ConstructorEnd{
+				Location: loc,
+				Scope:    s.name,
+				Outputs:  outputs,
+				Runtime:  c.clock().Since(start),
+				Err:      err,
+			})
+		}()
+	}
+
 	if n.callback != nil {
 		start := c.clock().Now()
 		// Wrap in separate func to include PanicErrors
@@ -179,6 +450,7 @@ func (n *constructorNode) Call(c containerStore) (err error) {
 				Name:    fmt.Sprintf("%v.%v", n.location.Package, n.location.Name),
 				Error:   err,
 				Runtime: c.clock().Since(start),
+				Attempt: attempt,
 			})
 		}()
 	}
@@ -194,21 +466,150 @@ func (n *constructorNode) Call(c containerStore) (err error) {
 		}()
 	}
 
-	receiver := newStagingContainerWriter()
-	results := c.invoker()(reflect.ValueOf(n.ctor), args)
+	receiver = newStagingContainerWriter()
+	start, panicked := c.clock().Now(), true
+	defer func() {
+		n.stats.record(c.clock().Since(start), c.clock().Since(callStart), err, panicked)
+	}()
+	var results []reflect.Value
+	if n.timeout > 0 {
+		results, err = invokeWithin(waitCtx, c.invoker(), reflect.ValueOf(n.ctor), args, func(results []reflect.Value) {
+			// The values are discarded, but whatever they hold must still
+			// be released when the Scope is closed.
+			late := newStagingContainerWriter()
+			if n.resultList.ExtractList(late, false /* decorating */, results) == nil {
+				target := n.s
+				if n.perScope {
+					target = c.asScope()
+				}
+				target.addCleanups(n.cleanupsOf(late, results))
+			}
+		})
+	} else {
+		results = c.invoker()(reflect.ValueOf(n.ctor), args)
+	}
+	panicked = false
+	if err != nil {
+		return nil, nil, n.canceledError(ctx, err)
+	}
 	if err = n.resultList.ExtractList(receiver, false /* decorating */, results); err != nil {
-		return errConstructorFailed{Func: n.location, Reason: err}
+		return nil, nil, errConstructorFailed{Func: n.location, Reason: err}
 	}
 
-	// Commit the result to the original container that this constructor
-	// was supplied to. The provided constructor is only used for a view of
-	// the rest of the graph to instantiate the dependencies of this
-	// container.
-	receiver.Commit(n.s)
-	n.called = true
+	return receiver, n.cleanupsOf(receiver, results), nil
+}
+
+// cleanupsOf returns the functions that clean up the values returned by
+// the constructor, which were staged in receiver.
+func (n *constructorNode) cleanupsOf(receiver *stagingContainerWriter, results []reflect.Value) []func() error {
+	if cleanup := n.resultList.Cleanup(results); cleanup != nil {
+		return []func() error{cleanup}
+	}
+	if n.s.trackClosers && n.resultList.cleanupIndex < 0 {
+		return closersOf(receiver)
+	}
 	return nil
 }
 
+// canceledError reports that the constructor was stopped because its
+// context was done with err. ctx is the context of its caller: if that
+// context isn't done, the timeout of the constructor elapsed.
+func (n *constructorNode) canceledError(ctx context.Context, err error) error {
+	cerr := CanceledError{Location: newLocation(n.location), Err: err}
+	if ctx.Err() == nil {
+		cerr.Timeout = n.timeout
+	}
+	return cerr
+}
+
+// invokeWithin calls fn with invoker on a separate goroutine and waits for
+// it to return, or for ctx to be done. It then returns the error of ctx
+// and leaves fn running. A panic in fn is re-raised on the calling
+// goroutine.
+//
+// If fn returns after the wait was given up, late is called with its
+// results on the goroutine that ran it.
+func invokeWithin(
+	ctx context.Context,
+	invoker invokerFn,
+	fn reflect.Value,
+	args []reflect.Value,
+	late func([]reflect.Value),
+) ([]reflect.Value, error) {
+	type outcome struct {
+		results []reflect.Value
+		panic   *buildPanic
+	}
+
+	var (
+		mu        sync.Mutex
+		abandoned bool   // whether the caller gave up waiting
+		goroutine uint64 // goroutine running fn while the caller waits
+		done      = make(chan outcome, 1)
+	)
+
+	// fn acts for the caller while it waits, so that calls into the
+	// container made by fn are recognized as its own. This must end with
+	// the wait.
+	caller := actingGoroutine()
+	release := func() {
+		if goroutine != 0 {
+			_actingFor.Delete(goroutine)
+			goroutine = 0
+		}
+	}
+
+	go func() {
+		mu.Lock()
+		if !abandoned {
+			goroutine = goroutineID()
+			_actingFor.Store(goroutine, caller)
+		}
+		mu.Unlock()
+
+		var o outcome
+		defer func() {
+			if p := recover(); p != nil {
+				o.panic = &buildPanic{value: p}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			release()
+			if !abandoned {
+				done <- o
+			} else if o.panic == nil {
+				late(o.results)
+			}
+		}()
+		o.results = invoker(fn, args)
+	}()
+
+	select {
+	case o := <-done:
+		if o.panic != nil {
+			panic(o.panic.value)
+		}
+		return o.results, nil
+	case <-ctx.Done():
+		mu.Lock()
+		defer mu.Unlock()
+
+		abandoned = true
+		release()
+		select {
+		case o := <-done:
+			// fn returned just as ctx was done. Its results are
+			// discarded all the same.
+			if o.panic == nil {
+				late(o.results)
+			}
+		default:
+		}
+		return nil, ctx.Err()
+	}
+}
+
 // stagingContainerWriter is a containerWriter that records the changes that
 // would be made to a containerWriter and defers them until Commit is called.
 type stagingContainerWriter struct {
@@ -242,15 +643,36 @@ func (sr *stagingContainerWriter) submitDecoratedGroupedValue(_ string, _ reflec
 	digerror.BugPanicf("stagingContainerWriter.submitDecoratedGroupedValue must never be called")
 }
 
-// Commit commits the received results to the provided containerWriter.
-func (sr *stagingContainerWriter) Commit(cw containerWriter) {
+// discard drops the received values with the given keys.
+func (sr *stagingContainerWriter) discard(keys map[key]struct{}) {
+	for k := range keys {
+		delete(sr.values, k)
+	}
+}
+
+// Commit commits the received results to the provided Scope and reports
+// what was committed. If scoped is set, values are committed as per-scope
+// values of s.
+func (sr *stagingContainerWriter) Commit(s *Scope, scoped bool) commitRecord {
+	s.valuesMu.Lock()
+	defer s.valuesMu.Unlock()
+
+	values := s.values
+	if scoped {
+		values = s.scopedValues
+	}
+
+	rec := commitRecord{scoped: scoped}
 	for k, v := range sr.values {
-		cw.setValue(k.name, k.t, v)
+		values[k] = v
+		rec.values = append(rec.values, k)
 	}
 
 	for k, vs := range sr.groups {
 		for _, v := range vs {
-			cw.submitGroupedValue(k.group, k.t, v)
+			rec.groups = append(rec.groups, groupEntry{key: k, index: len(s.groups[k])})
+			s.groups[k] = append(s.groups[k], v)
 		}
 	}
+	return rec
 }
@@ -43,8 +43,9 @@ func TestNewDotCtor(t *testing.T) {
 		Line:    24534,
 	}
 
-	ctor := newDotCtor(n)
+	ctor := newDotCtor(n, map[*Scope]int{s: 3})
 	assert.Equal(t, n.id, ctor.ID)
+	assert.Equal(t, 3, ctor.Scope)
 	assert.Equal(t, "function1", ctor.Name)
 	assert.Equal(t, "pkg1", ctor.Package)
 	assert.Equal(t, "file1", ctor.File)
@@ -61,7 +62,7 @@ func TestNodeAlreadyCalled(t *testing.T) {
 	require.False(t, n.called, "node must not have been called")
 
 	c := New()
-	require.NoError(t, n.Call(c.scope), "invoke failed")
+	require.NoError(t, n.Call(c.scope, nil), "invoke failed")
 	require.True(t, n.called, "node must be called")
-	require.NoError(t, n.Call(c.scope), "calling again should be okay")
+	require.NoError(t, n.Call(c.scope, nil), "calling again should be okay")
 }
@@ -103,6 +103,10 @@ type containerStore interface {
 	// Retrieves the value with the provided name and type, if any.
 	getValue(name string, t reflect.Type) (v reflect.Value, ok bool)
 
+	// Retrieves the value with the provided name and type that was built
+	// for this store by a per-scope constructor, if any.
+	getScopedValue(name string, t reflect.Type) (v reflect.Value, ok bool)
+
 	// Retrieves a decorated value with the provided name and type, if any.
 	getDecoratedValue(name string, t reflect.Type) (v reflect.Value, ok bool)
 
@@ -145,6 +149,13 @@ type containerStore interface {
 
 	// Returns a clock to use
 	clock() digclock.Clock
+
+	// Returns the workers to build dependencies in parallel with, or nil
+	// if dependencies must be built sequentially.
+	workerPool() *workerPool
+
+	// Returns the Scope backing this store.
+	asScope() *Scope
 }
 
 // New constructs a Container.
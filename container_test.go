@@ -67,4 +67,16 @@ func TestOptionStrings(t *testing.T) {
 
 		assert.Equal(t, "RecoverFromPanics()", fmt.Sprint(RecoverFromPanics()))
 	})
+
+	t.Run("ParallelConstruction", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, "ParallelConstruction(4)", fmt.Sprint(ParallelConstruction(4)))
+	})
+
+	t.Run("TrackClosers", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, "TrackClosers()", fmt.Sprint(TrackClosers()))
+	})
 }
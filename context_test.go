@@ -0,0 +1,376 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/dig"
+	"go.uber.org/dig/internal/digtest"
+)
+
+type ctxKey struct{}
+
+func TestInvokeContext(t *testing.T) {
+	t.Parallel()
+
+	type A struct{ ctx context.Context }
+	type B struct{}
+
+	t.Run("context parameters", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func(ctx context.Context) A { return A{ctx: ctx} })
+
+		type in struct {
+			dig.In
+
+			Ctx context.Context
+		}
+		ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+		require.NoError(t, c.InvokeContext(ctx, func(a A, ctx2 context.Context, p in) {
+			assert.Equal(t, ctx, a.ctx)
+			assert.Equal(t, ctx, ctx2)
+			assert.Equal(t, ctx, p.Ctx)
+		}))
+	})
+
+	t.Run("Invoke uses the background context", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireInvoke(func(ctx context.Context) {
+			assert.Equal(t, context.Background(), ctx)
+		})
+
+		child := c.Scope("child")
+		require.NoError(t, child.Invoke(func(ctx context.Context) {
+			assert.Equal(t, context.Background(), ctx)
+		}))
+	})
+
+	t.Run("provided context", func(t *testing.T) {
+		t.Parallel()
+
+		provided := context.WithValue(context.Background(), ctxKey{}, "provided")
+		c := digtest.New(t)
+		c.RequireProvide(func() context.Context { return provided })
+		require.NoError(t, c.InvokeContext(context.Background(), func(ctx context.Context) {
+			assert.Equal(t, provided, ctx)
+		}))
+	})
+
+	t.Run("named context is not satisfied", func(t *testing.T) {
+		t.Parallel()
+
+		type in struct {
+			dig.In
+
+			Ctx context.Context `name:"ctx"`
+		}
+		c := digtest.New(t)
+		err := c.InvokeContext(context.Background(), func(in) {})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `missing type: context.Context[name="ctx"]`)
+	})
+
+	t.Run("nil context", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		err := c.InvokeContext(nil, func() {}) //nolint:staticcheck // nil context is the point of the test
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "can't invoke with a nil context")
+	})
+
+	t.Run("canceled before starting", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() A {
+			t.Error("constructor must not be called")
+			return A{}
+		})
+		c.RequireProvide(func(A) B { return B{} })
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := c.InvokeContext(ctx, func(B) {
+			t.Error("function must not be called")
+		})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		var cerr dig.CanceledError
+		require.ErrorAs(t, err, &cerr)
+		assert.Equal(t, "TestInvokeContext.func6.1", cerr.Location.Name)
+		assert.Zero(t, cerr.Timeout)
+		assert.Equal(t, cerr, dig.RootCause(err))
+		assert.Contains(t, err.Error(), "stopped before calling")
+	})
+
+	t.Run("canceled between constructors", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var calls int
+		c := digtest.New(t)
+		c.RequireProvide(func() A {
+			calls++
+			cancel()
+			return A{}
+		})
+		c.RequireProvide(func(A) B { return B{} })
+
+		err := c.InvokeContext(ctx, func(B) {
+			t.Error("function must not be called")
+		})
+		var cerr dig.CanceledError
+		require.ErrorAs(t, err, &cerr)
+		assert.Equal(t, "TestInvokeContext.func7.2", cerr.Location.Name)
+
+		// Values built before construction stopped are kept.
+		c.RequireInvoke(func(A) {})
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("canceled before invoking", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		c := digtest.New(t)
+		c.RequireProvide(func() A {
+			cancel()
+			return A{}
+		})
+
+		err := c.InvokeContext(ctx, func(A) {
+			t.Error("function must not be called")
+		})
+		var cerr dig.CanceledError
+		require.ErrorAs(t, err, &cerr)
+		assert.Equal(t, "TestInvokeContext.func8.2", cerr.Location.Name)
+	})
+
+	t.Run("canceled before decorating", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() A { return A{} })
+		c.RequireInvoke(func(A) {})
+		c.RequireDecorate(func(a A) A {
+			t.Error("decorator must not be called")
+			return a
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := c.InvokeContext(ctx, func(A) {})
+		var cerr dig.CanceledError
+		require.ErrorAs(t, err, &cerr)
+		assert.Equal(t, "TestInvokeContext.func9.3", cerr.Location.Name)
+	})
+}
+
+func TestTimeout(t *testing.T) {
+	t.Parallel()
+
+	type A struct{}
+	type B struct{}
+
+	t.Run("constructor does not return in time", func(t *testing.T) {
+		t.Parallel()
+
+		release := make(chan struct{})
+		defer close(release)
+
+		c := digtest.New(t)
+		c.RequireProvide(func() A {
+			<-release
+			return A{}
+		}, dig.Timeout(10*time.Millisecond))
+		c.RequireProvide(func(A) B { return B{} })
+
+		err := c.Invoke(func(B) {})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		var cerr dig.CanceledError
+		require.ErrorAs(t, err, &cerr)
+		assert.Equal(t, "TestTimeout.func1.1", cerr.Location.Name)
+		assert.Equal(t, 10*time.Millisecond, cerr.Timeout)
+		assert.Contains(t, err.Error(), "did not return within 10ms: context deadline exceeded")
+	})
+
+	t.Run("Clone", func(t *testing.T) {
+		t.Parallel()
+
+		release := make(chan struct{})
+		defer close(release)
+
+		c := digtest.New(t)
+		c.RequireProvide(func() A {
+			<-release
+			return A{}
+		}, dig.Timeout(10*time.Millisecond))
+
+		err := c.Clone().Invoke(func(A) {})
+		require.Error(t, err)
+
+		var cerr dig.CanceledError
+		require.ErrorAs(t, err, &cerr)
+		assert.Equal(t, 10*time.Millisecond, cerr.Timeout)
+	})
+
+	t.Run("context of the constructor", func(t *testing.T) {
+		t.Parallel()
+
+		type dep struct{ ctx context.Context }
+
+		c := digtest.New(t)
+		c.RequireProvide(func(ctx context.Context) *dep { return &dep{ctx: ctx} })
+		c.RequireProvide(func(ctx context.Context, d *dep) A {
+			_, ok := ctx.Deadline()
+			assert.False(t, ok, "context must not be bound to the timeout")
+			assert.Equal(t, "value", ctx.Value(ctxKey{}))
+			return A{}
+		}, dig.Timeout(time.Minute))
+
+		ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+		require.NoError(t, c.InvokeContext(ctx, func(A) {}))
+
+		// Dependencies are shared with later Invokes. Their context must
+		// outlive the constructor.
+		c.RequireInvoke(func(d *dep) {
+			assert.NoError(t, d.ctx.Err())
+			assert.Equal(t, "value", d.ctx.Value(ctxKey{}))
+		})
+	})
+
+	t.Run("late values are cleaned up", func(t *testing.T) {
+		t.Parallel()
+
+		release := make(chan struct{})
+		var cleaned atomic.Bool
+
+		c := digtest.New(t)
+		c.RequireProvide(func() (A, func()) {
+			<-release
+			return A{}, func() { cleaned.Store(true) }
+		}, dig.Cleanup(), dig.Timeout(10*time.Millisecond))
+
+		err := c.Invoke(func(A) {})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		close(release)
+		assert.Eventually(t, func() bool {
+			return c.Close() == nil && cleaned.Load()
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("late values are closed", func(t *testing.T) {
+		t.Parallel()
+
+		release := make(chan struct{})
+		var closed []string
+
+		c := digtest.New(t, dig.TrackClosers())
+		c.RequireProvide(func() *closer {
+			<-release
+			return &closer{name: "late", closed: &closed}
+		}, dig.Timeout(10*time.Millisecond))
+
+		require.Error(t, c.Invoke(func(*closer) {}))
+
+		close(release)
+		assert.Eventually(t, func() bool {
+			return c.Close() == nil && len(closed) == 1
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("constructor returns in time", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() A { return A{} }, dig.Timeout(time.Minute))
+		c.RequireProvide(func() (B, error) {
+			return B{}, errors.New("great sadness")
+		}, dig.Timeout(time.Minute))
+
+		c.RequireInvoke(func(A) {})
+		err := c.Invoke(func(B) {})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "great sadness")
+		assert.NotErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("caller context is done first", func(t *testing.T) {
+		t.Parallel()
+
+		release := make(chan struct{})
+		defer close(release)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		c := digtest.New(t)
+		c.RequireProvide(func() A {
+			cancel()
+			<-release
+			return A{}
+		}, dig.Timeout(time.Minute))
+
+		err := c.InvokeContext(ctx, func(A) {})
+		var cerr dig.CanceledError
+		require.ErrorAs(t, err, &cerr)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Zero(t, cerr.Timeout)
+	})
+
+	t.Run("panics", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t, dig.RecoverFromPanics())
+		c.RequireProvide(func() A { panic("great sadness") }, dig.Timeout(time.Minute))
+
+		err := c.Invoke(func(A) {})
+		var pe dig.PanicError
+		require.ErrorAs(t, err, &pe)
+		assert.Equal(t, "great sadness", pe.Panic)
+	})
+
+	t.Run("negative timeout", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		err := c.Provide(func() A { return A{} }, dig.Timeout(-time.Second))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid dig.Timeout(-1s): timeout must not be negative")
+	})
+}
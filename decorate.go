@@ -28,18 +28,9 @@ import (
 	"go.uber.org/dig/internal/dot"
 )
 
-type decoratorState int
-
-const (
-	decoratorReady decoratorState = iota
-	decoratorOnStack
-	decoratorCalled
-)
-
 type decorator interface {
-	Call(c containerStore) error
+	Call(c containerStore, bs *buildState) error
 	ID() dot.CtorID
-	State() decoratorState
 }
 
 type decoratorNode struct {
@@ -51,8 +42,9 @@ type decoratorNode struct {
 	// Location where this function was defined.
 	location *digreflect.Func
 
-	// Current state of this decorator
-	state decoratorState
+	// Tracks whether this decorator was already called, and synchronizes
+	// concurrent calls to it.
+	callGate
 
 	// Parameters of the decorator.
 	params paramList
@@ -60,6 +52,9 @@ type decoratorNode struct {
 	// Results of the decorator.
 	results resultList
 
+	// Keys of the values decorated by this decorator.
+	keys []key
+
 	// Order of this node in each Scopes' graphHolders.
 	orders map[*Scope]int
 
@@ -71,6 +66,9 @@ type decoratorNode struct {
 
 	// BeforeCallback for this decorator, if there is one
 	beforeCallback BeforeCallback
+
+	// Statistics on the calls made to the decorator.
+	stats callStats
 }
 
 func newDecoratorNode(dcor interface{}, s *Scope, opts decorateOptions) (*decoratorNode, error) {
@@ -103,13 +101,35 @@ func newDecoratorNode(dcor interface{}, s *Scope, opts decorateOptions) (*decora
 	return n, nil
 }
 
-func (n *decoratorNode) Call(s containerStore) (err error) {
-	if n.state == decoratorCalled {
+func (n *decoratorNode) Call(s containerStore, bs *buildState) (err error) {
+	if err, ok := bs.failed(&n.callGate); ok {
+		return err
+	}
+
+	call, owner := n.enter()
+	if call == nil {
 		return nil
 	}
+	if !owner {
+		span := n.s.startSpan(bs, SpanWait, n.location)
+		err := call.wait()
+		span.end(err)
+		return err
+	}
+	defer func() { n.exit(call, err, recover()) }()
+	defer func() {
+		if err != nil {
+			bs.fail(&n.callGate, err)
+		}
+	}()
 
-	n.state = decoratorOnStack
+	callStart := s.clock().Now()
+	span := n.s.startSpan(bs, SpanDecorator, n.location)
+	defer func() { span.end(err) }()
 
+	// Values requested by the decorator from the graph must not be
+	// decorated by it. Record it as running so that they aren't.
+	bs = bs.push(n).within(span)
 	if err := shallowCheckDependencies(s, n.params); err != nil {
 		return errMissingDependencies{
 			Func:   n.location,
@@ -117,19 +137,39 @@ func (n *decoratorNode) Call(s containerStore) (err error) {
 		}
 	}
 
-	args, err := n.params.BuildList(n.s)
+	args, err := n.params.BuildList(n.s, bs)
 	if err != nil {
 		return errArgumentsFailed{
 			Func:   n.location,
 			Reason: err,
 		}
 	}
+	if err := bs.checkContext(n.location); err != nil {
+		return err
+	}
 	if n.beforeCallback != nil {
 		n.beforeCallback(BeforeCallbackInfo{
-			Name: fmt.Sprintf("%v.%v", n.location.Package, n.location.Name),
+			Name:    fmt.Sprintf("%v.%v", n.location.Package, n.location.Name),
+			Attempt: 1,
 		})
 	}
 
+	if n.s.emitting() {
+		loc, outputs := newLocation(n.location), newOutputs(n.results)
+		n.s.emit(DecoratorStart{Location: loc, Scope: n.s.name, Outputs: outputs})
+
+		start := s.clock().Now()
+		defer func() {
+			n.s.emit(DecoratorEnd{
+				Location: loc,
+				Scope:    n.s.name,
+				Outputs:  outputs,
+				Runtime:  s.clock().Since(start),
+				Err:      err,
+			})
+		}()
+	}
+
 	if n.callback != nil {
 		start := s.clock().Now()
 		// Wrap in separate func to include PanicErrors
@@ -138,6 +178,7 @@ func (n *decoratorNode) Call(s containerStore) (err error) {
 				Name:    fmt.Sprintf("%v.%v", n.location.Package, n.location.Name),
 				Error:   err,
 				Runtime: s.clock().Since(start),
+				Attempt: 1,
 			})
 		}()
 	}
@@ -153,18 +194,26 @@ func (n *decoratorNode) Call(s containerStore) (err error) {
 		}()
 	}
 
+	start, panicked := s.clock().Now(), true
+	defer func() {
+		n.stats.record(s.clock().Since(start), s.clock().Since(callStart), err, panicked)
+	}()
 	results := s.invoker()(reflect.ValueOf(n.dcor), args)
-	if err = n.results.ExtractList(n.s, true /* decorated */, results); err != nil {
+	panicked = false
+	if err := n.results.ExtractList(n.s, true /* decorated */, results); err != nil {
 		return err
 	}
-	n.state = decoratorCalled
+
+	bs.record(func() []*cleanup {
+		n.s.undoDecorate(n.keys)
+		n.reset()
+		return nil
+	}, false /* hasCleanups */)
 	return nil
 }
 
 func (n *decoratorNode) ID() dot.CtorID { return n.id }
 
-func (n *decoratorNode) State() decoratorState { return n.state }
-
 // DecorateOption modifies the default behavior of Decorate.
 type DecorateOption interface {
 	apply(*decorateOptions)
@@ -198,8 +247,13 @@ type DecorateInfo struct {
 	ID      ID
 	Inputs  []*Input
 	Outputs []*Output
+
+	location Location
 }
 
+// Location returns where the decorator was defined.
+func (i *DecorateInfo) Location() Location { return i.location }
+
 // Decorate provides a decorator for a type that has already been provided in the Container.
 // Decorations at this level affect all scopes of the container.
 // See Scope.Decorate for information on how to use this method.
@@ -250,6 +304,28 @@ func (s *Scope) Decorate(decorator interface{}, opts ...DecorateOption) error {
 		opt.apply(&options)
 	}
 
+	if !s.emitting() {
+		return s.decorate(decorator, options)
+	}
+
+	// The outputs of the decorator are reported to event handlers.
+	if options.Info == nil {
+		options.Info = new(DecorateInfo)
+	}
+	err := s.decorate(decorator, options)
+	s.emit(Decorated{
+		Location: funcLocation(decorator),
+		Scope:    s.name,
+		Outputs:  options.Info.Outputs,
+		Err:      err,
+	})
+	return err
+}
+
+func (s *Scope) decorate(decorator interface{}, options decorateOptions) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	dn, err := newDecoratorNode(decorator, s, options)
 	if err != nil {
 		return err
@@ -259,36 +335,30 @@ func (s *Scope) Decorate(decorator interface{}, opts ...DecorateOption) error {
 	if err != nil {
 		return err
 	}
+	dn.keys = keys
 	for _, k := range keys {
 		if _, ok := s.decorators[k]; ok {
 			return newErrInvalidInput(
 				fmt.Sprintf("cannot decorate using function %v: %s already decorated", dn.dtype, k), nil)
 		}
+		if p, ok := s.findUncachedProvider(k); ok {
+			kind := "transient"
+			if p.PerScope() {
+				kind = "per-scope"
+			}
+			return newErrInvalidInput(
+				fmt.Sprintf("cannot decorate using function %v: %s is provided by a %s constructor", dn.dtype, k, kind), nil)
+		}
+	}
+	for _, k := range keys {
 		s.decorators[k] = dn
 	}
 
 	if info := options.Info; info != nil {
-		params := dn.params.DotParam()
-		results := dn.results.DotResult()
 		info.ID = (ID)(dn.id)
-		info.Inputs = make([]*Input, len(params))
-		info.Outputs = make([]*Output, len(results))
-
-		for i, param := range params {
-			info.Inputs[i] = &Input{
-				t:        param.Type,
-				optional: param.Optional,
-				name:     param.Name,
-				group:    param.Group,
-			}
-		}
-		for i, res := range results {
-			info.Outputs[i] = &Output{
-				t:     res.Type,
-				name:  res.Name,
-				group: res.Group,
-			}
-		}
+		info.Inputs = newInputs(dn.params)
+		info.Outputs = newOutputs(dn.results)
+		info.location = newLocation(dn.location)
 	}
 	return nil
 }
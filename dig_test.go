@@ -28,6 +28,8 @@ import (
 	"math/rand"
 	"os"
 	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -4341,3 +4343,278 @@ func TestEndToEndSuccessWithAliases(t *testing.T) {
 		})
 	})
 }
+
+func TestConcurrency(t *testing.T) {
+	t.Parallel()
+
+	t.Run("concurrent invokes call constructors once", func(t *testing.T) {
+		t.Parallel()
+
+		type A struct{}
+		type B struct{ a *A }
+
+		var aCalls, bCalls int32
+		release := make(chan struct{})
+		c := digtest.New(t)
+		c.RequireProvide(func() *A {
+			atomic.AddInt32(&aCalls, 1)
+			<-release
+			return &A{}
+		})
+		c.RequireProvide(func(a *A) *B {
+			atomic.AddInt32(&bCalls, 1)
+			return &B{a: a}
+		})
+
+		const n = 20
+		var (
+			wg      sync.WaitGroup
+			results [n]*B
+		)
+		for i := 0; i < n; i++ {
+			i := i
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, c.Invoke(func(b *B) { results[i] = b }))
+			}()
+		}
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(&aCalls))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&bCalls))
+		for _, b := range results {
+			assert.Same(t, results[0], b)
+		}
+	})
+
+	t.Run("scope per goroutine", func(t *testing.T) {
+		t.Parallel()
+
+		type Request struct{ id int }
+		type Handler struct{ req *Request }
+
+		var loggerCalls int32
+		c := digtest.New(t)
+		c.RequireProvide(func() *bytes.Buffer {
+			atomic.AddInt32(&loggerCalls, 1)
+			return new(bytes.Buffer)
+		})
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			i := i
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				s := c.Scope(fmt.Sprintf("request %d", i))
+				s.RequireProvide(func() *Request { return &Request{id: i} })
+				s.RequireProvide(func(r *Request, _ *bytes.Buffer) *Handler {
+					return &Handler{req: r}
+				})
+				s.RequireInvoke(func(h *Handler) {
+					assert.Equal(t, i, h.req.id)
+				})
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(&loggerCalls))
+	})
+
+	t.Run("concurrent provide and invoke", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() int { return 42 })
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			i := i
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				c.RequireProvide(func(v int) string { return fmt.Sprint(v) }, dig.Name(fmt.Sprint(i)))
+			}()
+			go func() {
+				defer wg.Done()
+				c.RequireInvoke(func(v int) { assert.Equal(t, 42, v) })
+			}()
+		}
+		wg.Wait()
+
+		for i := 0; i < 20; i++ {
+			v, err := dig.ResolveNamed[string](c, fmt.Sprint(i))
+			require.NoError(t, err)
+			assert.Equal(t, "42", v)
+		}
+	})
+
+	t.Run("concurrent decorated values", func(t *testing.T) {
+		t.Parallel()
+
+		var decorations int32
+		release := make(chan struct{})
+		c := digtest.New(t)
+		c.RequireProvide(func() int { return 1 })
+		c.RequireDecorate(func(v int) int {
+			atomic.AddInt32(&decorations, 1)
+			<-release
+			return v + 1
+		})
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				c.RequireInvoke(func(v int) {
+					assert.Equal(t, 2, v, "must observe decorated value")
+				})
+			}()
+		}
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(&decorations))
+	})
+
+	t.Run("concurrent value groups", func(t *testing.T) {
+		t.Parallel()
+
+		type params struct {
+			dig.In
+
+			Values []int `group:"values"`
+		}
+
+		c := digtest.New(t)
+		for i := 0; i < 10; i++ {
+			i := i
+			c.RequireProvide(func() int { return i }, dig.Group("values"))
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				c.RequireInvoke(func(p params) {
+					assert.Len(t, p.Values, 10)
+				})
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("failures are shared with waiters and retried later", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int32
+		release := make(chan struct{})
+		c := digtest.New(t)
+		c.RequireProvide(func() (int, error) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				<-release
+				return 0, errors.New("great sadness")
+			}
+			return 42, nil
+		})
+
+		var wg sync.WaitGroup
+		started := make(chan struct{})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			close(started)
+			assert.ErrorContains(t, c.Invoke(func(int) {}), "great sadness")
+		}()
+		<-started
+		close(release)
+		wg.Wait()
+
+		c.RequireInvoke(func(v int) { assert.Equal(t, 42, v) })
+	})
+
+	t.Run("reentrant invoke", func(t *testing.T) {
+		t.Parallel()
+
+		type A struct{}
+		type B struct{ a *A }
+
+		c := digtest.New(t)
+		c.RequireProvide(func() *A { return &A{} })
+		c.RequireProvide(func() (*B, error) {
+			b := &B{}
+			err := c.Invoke(func(a *A) { b.a = a })
+			return b, err
+		})
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			c.RequireInvoke(func(b *B) { assert.NotNil(t, b.a) })
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("reentrant Invoke deadlocked")
+		}
+	})
+
+	t.Run("reentrant invoke of the value being built", func(t *testing.T) {
+		t.Parallel()
+
+		type A struct{}
+		type B struct{}
+
+		tests := []struct {
+			desc string
+			opts []dig.ProvideOption
+		}{
+			{desc: "same goroutine"},
+			{desc: "with timeout", opts: []dig.ProvideOption{dig.Timeout(time.Minute)}},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.desc, func(t *testing.T) {
+				t.Parallel()
+
+				c := digtest.New(t)
+				c.RequireProvide(func() (*A, error) {
+					return &A{}, c.Invoke(func(*B) {})
+				}, tt.opts...)
+				c.RequireProvide(func(*A) *B { return &B{} })
+
+				done := make(chan error)
+				go func() {
+					done <- c.Invoke(func(*A) {})
+				}()
+
+				select {
+				case err := <-done:
+					require.Error(t, err)
+					assert.True(t, dig.IsCycleDetected(err), "expected a cycle error, got %v", err)
+					assert.Contains(t, err.Error(), "depends on func(*dig_test.A) *dig_test.B provided by")
+				case <-time.After(5 * time.Second):
+					t.Fatal("reentrant Invoke deadlocked")
+				}
+			})
+		}
+	})
+
+	t.Run("reentrant provide", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() (int, error) {
+			return 42, c.Provide(func(v int) string { return fmt.Sprint(v) })
+		})
+		c.RequireInvoke(func(int) {})
+		c.RequireInvoke(func(s string) { assert.Equal(t, "42", s) })
+	})
+}
@@ -96,6 +96,32 @@
 // The constructor will be called with all other dependencies and no variadic
 // arguments.
 //
+// # Cleanup
+//
+// Constructors provided with the Cleanup option return a cleanup function
+// after their other results to release what they built. Cleanup functions
+// have the type func() or func() error and are not provided to the
+// container.
+//
+//	func NewDBConn(cfg *Config) (*sql.DB, func() error, error) {
+//	  db, err := sql.Open("mysql", cfg.DSN)
+//	  if err != nil {
+//	    return nil, nil, err
+//	  }
+//	  return db, db.Close, nil
+//	}
+//
+//	c.Provide(NewDBConn, dig.Cleanup())
+//
+// Closing the container runs the cleanup functions of all values it built,
+// in reverse order of construction.
+//
+//	defer c.Close()
+//
+// If an Invoke fails, the values built with cleanup functions during that
+// Invoke are cleaned up and removed from the container right away, unless
+// another Invoke ran at the same time and may be using them.
+//
 // # Invoke
 //
 // Types added to the container may be consumed by using the Invoke method.
@@ -122,6 +148,14 @@
 // Any error returned by the invoked function is propagated back to the
 // caller.
 //
+// Single values can also be retrieved from the container with the generic
+// Resolve function, without declaring a function to invoke.
+//
+//	server, err := dig.Resolve[*http.Server](c)
+//	if err != nil {
+//	  // ...
+//	}
+//
 // # Parameter Objects
 //
 // Constructors declare their dependencies as function parameters. This can
@@ -218,6 +252,18 @@
 // The optional tag also allows adding new dependencies without breaking
 // existing consumers of the constructor.
 //
+// # Lazy Dependencies
+//
+// Constructors can depend on dig.Lazy[T] instead of T to build the value
+// only when they need it, by calling Get.
+//
+//	func NewExporter(db dig.Lazy[*sql.DB]) *Exporter {
+//	  return &Exporter{db: db}
+//	}
+//
+// Lazy dependencies are ignored when looking for cycles, so they can be used
+// to let two constructors depend on each other.
+//
 // # Named Values
 //
 // Some use cases call for multiple values of the same type. Dig allows adding
@@ -345,4 +391,19 @@
 //	  Handler []int `group:"server"`         // [][]int from dig.In
 //	  Handler []int `group:"server,flatten"` // []int from dig.In
 //	}
+//
+// # Concurrency
+//
+// Containers and Scopes are safe for concurrent use. Provide, Decorate,
+// Invoke and Scope may be called from multiple goroutines across the same
+// scope tree, for example to create one child scope per request.
+//
+// Each constructor is still called at most once. If several goroutines need
+// a value that is being constructed, one of them calls the constructor and
+// the others wait for its result. A constructor that fails may be retried by
+// a later Invoke.
+//
+// Constructors may call Invoke or Provide on the container that is
+// constructing them. Such calls must not depend on the values the
+// constructor itself is in the middle of producing.
 package dig // import "go.uber.org/dig"
@@ -21,11 +21,13 @@
 package dig
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
 	"reflect"
 	"sort"
+	"time"
 
 	"go.uber.org/dig/internal/digreflect"
 	"go.uber.org/dig/internal/dot"
@@ -104,6 +106,43 @@ func (e PanicError) Error() string {
 	return fmt.Sprint(e)
 }
 
+// A CanceledError occurs when the context given to InvokeContext is done
+// before the invoked function and all its dependencies were called, or
+// when a constructor provided with the [Timeout] option does not return in
+// time. It names the function that construction stopped at, and wraps the
+// error of the context so that the reason can be checked with errors.Is:
+//
+//	if errors.Is(err, context.DeadlineExceeded) {
+//		// Startup took too long.
+//	}
+//
+// Like PanicError, a CanceledError does not implement dig.Error, meaning it
+// will be returned from [RootCause].
+type CanceledError struct {
+	// Location of the constructor, decorator or invoked function that was
+	// about to be called, or that did not return in time.
+	Location Location
+
+	// Timeout of the constructor if it did not return within the duration
+	// given to the Timeout option. It's zero otherwise.
+	Timeout time.Duration
+
+	// Error of the context: context.Canceled or context.DeadlineExceeded.
+	Err error
+}
+
+func (e CanceledError) Error() string {
+	if e.Timeout > 0 {
+		return fmt.Sprintf("%v did not return within %v: %v", e.Location, e.Timeout, e.Err)
+	}
+	return fmt.Sprintf("stopped before calling %v: %v", e.Location, e.Err)
+}
+
+// Unwrap returns the error of the context.
+func (e CanceledError) Unwrap() error {
+	return e.Err
+}
+
 // formatError will call a dig.Error's writeMessage() method to print the error message
 // and then will automatically attempt to print errors wrapped underneath (which can create
 // a recursive effect if the wrapped error's Format() method then points back to this function).
@@ -235,6 +274,38 @@ func (e errConstructorFailed) Format(w fmt.State, c rune) {
 	formatError(e, w, c)
 }
 
+// errRetriesFailed is returned when a constructor provided with the Retry
+// option failed on every attempt it was given.
+type errRetriesFailed struct {
+	// Errors returned by the constructor on the attempts before the
+	// last one.
+	Attempts []error
+
+	// Error of the last attempt.
+	Reason error
+}
+
+var _ digError = errRetriesFailed{}
+
+func (e errRetriesFailed) Error() string { return fmt.Sprint(e) }
+
+func (e errRetriesFailed) Unwrap() error { return e.Reason }
+
+func (e errRetriesFailed) writeMessage(w io.Writer, _ string) {
+	fmt.Fprintf(w, "failed after %d attempts (", len(e.Attempts)+1)
+	for i, err := range e.Attempts {
+		if i > 0 {
+			io.WriteString(w, "; ")
+		}
+		fmt.Fprintf(w, "attempt %d: %v", i+1, err)
+	}
+	io.WriteString(w, ")")
+}
+
+func (e errRetriesFailed) Format(w fmt.State, c rune) {
+	formatError(e, w, c)
+}
+
 // errArgumentsFailed is returned when a function could not be run because one
 // of its dependencies failed to build for any reason.
 type errArgumentsFailed struct {
@@ -256,6 +327,60 @@ func (e errArgumentsFailed) Format(w fmt.State, c rune) {
 	formatError(e, w, c)
 }
 
+// errDependenciesFailed is returned when more than one of the dependencies
+// of a function failed to build. This only happens with CollectErrors.
+type errDependenciesFailed struct {
+	Errors []error // inv: len > 1
+}
+
+var _ digError = errDependenciesFailed{}
+
+// newErrDependenciesFailed reports the given errors of dependencies,
+// ignoring nil ones. It returns nil if there are no errors, and the error
+// as is if there is only one.
+func newErrDependenciesFailed(errs []error) error {
+	var failed []error
+	for _, err := range errs {
+		if df, ok := err.(errDependenciesFailed); ok {
+			failed = append(failed, df.Errors...)
+		} else if err != nil {
+			failed = append(failed, err)
+		}
+	}
+
+	switch len(failed) {
+	case 0:
+		return nil
+	case 1:
+		return failed[0]
+	default:
+		return errDependenciesFailed{Errors: failed}
+	}
+}
+
+func (e errDependenciesFailed) Error() string { return fmt.Sprint(e) }
+
+func (e errDependenciesFailed) Unwrap() []error { return e.Errors }
+
+func (e errDependenciesFailed) writeMessage(w io.Writer, verb string) {
+	fmt.Fprintf(w, "%d dependencies failed to build:", len(e.Errors))
+	writeErrorList(w, verb, e.Errors)
+}
+
+func (e errDependenciesFailed) Format(w fmt.State, c rune) {
+	formatError(e, w, c)
+}
+
+// writeErrorList writes errs as a numbered list with one error per line.
+// Errors that span several lines are indented under their number.
+func writeErrorList(w io.Writer, verb string, errs []error) {
+	for i, err := range errs {
+		var b bytes.Buffer
+		fmt.Fprintf(&b, verb, err)
+		fmt.Fprintf(w, "\n[%d] %s", i+1, bytes.ReplaceAll(b.Bytes(), []byte("\n"), []byte("\n    ")))
+	}
+}
+
 // errMissingDependencies is returned when the dependencies of a function are
 // not available in the container.
 type errMissingDependencies struct {
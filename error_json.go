@@ -0,0 +1,250 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"go.uber.org/dig/internal/digreflect"
+)
+
+// jsonError is the structured form of a dig error that MarshalJSON and
+// LogValue report. Each level of the error chain only carries its own
+// message: the errors it wraps are reported under Cause, or Errors when
+// there are several of them.
+type jsonError struct {
+	Kind     string            `json:"kind"`
+	Message  string            `json:"message"`
+	Func     *jsonFunc         `json:"func,omitempty"`
+	Key      *jsonKey          `json:"key,omitempty"`
+	Missing  []jsonMissingType `json:"missing,omitempty"`
+	Path     []jsonPathEntry   `json:"path,omitempty"`
+	Scope    string            `json:"scope,omitempty"`
+	Attempts []string          `json:"attempts,omitempty"`
+	Panic    string            `json:"panic,omitempty"`
+	Timeout  string            `json:"timeout,omitempty"`
+	Cause    *jsonError        `json:"cause,omitempty"`
+	Errors   []*jsonError      `json:"errors,omitempty"`
+}
+
+type jsonFunc struct {
+	Name    string `json:"name"`
+	Package string `json:"package"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+}
+
+type jsonKey struct {
+	Type  string `json:"type"`
+	Name  string `json:"name,omitempty"`
+	Group string `json:"group,omitempty"`
+}
+
+type jsonMissingType struct {
+	Key         jsonKey   `json:"key"`
+	Suggestions []jsonKey `json:"suggestions,omitempty"`
+}
+
+type jsonPathEntry struct {
+	Key  *jsonKey  `json:"key,omitempty"`
+	Func *jsonFunc `json:"func"`
+}
+
+func newJSONFunc(f *digreflect.Func) *jsonFunc {
+	if f == nil {
+		return nil
+	}
+	return &jsonFunc{Name: f.Name, Package: f.Package, File: f.File, Line: f.Line}
+}
+
+func newJSONKey(k key) jsonKey {
+	return jsonKey{Type: k.t.String(), Name: k.name, Group: k.group}
+}
+
+// newJSONKeyRef is like newJSONKey, but returns nil for unset keys.
+func newJSONKeyRef(k key) *jsonKey {
+	if k.t == nil {
+		return nil
+	}
+	jk := newJSONKey(k)
+	return &jk
+}
+
+// newJSONError builds the structured form of err. Errors that don't come
+// from dig are reported with their message only, and the errors they wrap
+// are not inspected.
+func newJSONError(err error) *jsonError {
+	de, ok := err.(Error)
+	if !ok {
+		je := &jsonError{Kind: "error", Message: err.Error()}
+		switch e := err.(type) {
+		case PanicError:
+			je.Kind = "panic"
+			je.Func = newJSONFunc(e.fn)
+			je.Panic = fmt.Sprint(e.Panic)
+		case CanceledError:
+			je.Kind = "canceled"
+			je.Func = &jsonFunc{
+				Name:    e.Location.Name,
+				Package: e.Location.Package,
+				File:    e.Location.File,
+				Line:    e.Location.Line,
+			}
+			if e.Timeout > 0 {
+				je.Timeout = e.Timeout.String()
+			}
+		}
+		return je
+	}
+
+	var msg strings.Builder
+	de.writeMessage(&msg, "%v")
+	je := &jsonError{Message: msg.String()}
+
+	switch e := err.(type) {
+	case errInvalidInput:
+		je.Kind = "invalidInput"
+	case errInvalidGroupOption:
+		je.Kind = "invalidGroupOption"
+	case errProvide:
+		je.Kind = "provideFailed"
+		je.Func = newJSONFunc(e.Func)
+	case errConstructorFailed:
+		je.Kind = "constructorFailed"
+		je.Func = newJSONFunc(e.Func)
+	case errRetriesFailed:
+		je.Kind = "retriesFailed"
+		for _, a := range e.Attempts {
+			je.Attempts = append(je.Attempts, a.Error())
+		}
+	case errArgumentsFailed:
+		je.Kind = "argumentsFailed"
+		je.Func = newJSONFunc(e.Func)
+	case errDependenciesFailed:
+		je.Kind = "dependenciesFailed"
+	case errMissingDependencies:
+		je.Kind = "missingDependencies"
+		je.Func = newJSONFunc(e.Func)
+	case errParamSingleFailed:
+		je.Kind = "paramFailed"
+		je.Key = newJSONKeyRef(e.Key)
+	case errParamGroupFailed:
+		je.Kind = "groupFailed"
+		je.Key = newJSONKeyRef(e.Key)
+	case errMissingTypes:
+		je.Kind = "missingTypes"
+		for _, mt := range e {
+			jmt := jsonMissingType{Key: newJSONKey(mt.Key)}
+			for _, sug := range mt.suggestions {
+				jmt.Suggestions = append(jmt.Suggestions, newJSONKey(sug))
+			}
+			je.Missing = append(je.Missing, jmt)
+		}
+	case errCycleDetected:
+		je.Kind = "cycleDetected"
+		if e.scope != nil {
+			je.Scope = e.scope.name
+		}
+		for _, entry := range e.Path {
+			je.Path = append(je.Path, jsonPathEntry{
+				Key:  newJSONKeyRef(entry.Key),
+				Func: newJSONFunc(entry.Func),
+			})
+		}
+	case errMissingDependencyPath:
+		je.Kind = "missingDependencyPath"
+		for _, f := range e.Path {
+			je.Path = append(je.Path, jsonPathEntry{
+				Key:  newJSONKeyRef(f.Key),
+				Func: newJSONFunc(f.Func),
+			})
+		}
+	case errVerifyFailed:
+		je.Kind = "verifyFailed"
+	case errCleanupFailed:
+		je.Kind = "cleanupFailed"
+		for _, e := range e.Errors {
+			je.Errors = append(je.Errors, newJSONError(e))
+		}
+	default:
+		je.Kind = "error"
+	}
+
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		if cause := u.Unwrap(); cause != nil {
+			je.Cause = newJSONError(cause)
+		}
+	case interface{ Unwrap() []error }:
+		for _, e := range u.Unwrap() {
+			je.Errors = append(je.Errors, newJSONError(e))
+		}
+	}
+	return je
+}
+
+// MarshalJSON reports the key as a JSON object with its type, and its name
+// or group if it has one. The zero Key is reported as null.
+func (k Key) MarshalJSON() ([]byte, error) {
+	return json.Marshal(newJSONKeyRef(key{t: k.Type, name: k.Name, group: k.Group}))
+}
+
+// MarshalJSON reports the panic as a JSON object with the location of the
+// function that panicked and the value it panicked with.
+func (e PanicError) MarshalJSON() ([]byte, error) { return json.Marshal(newJSONError(e)) }
+
+// MarshalJSON reports the error as a JSON object with the location of the
+// function that was stopped and its timeout, if it had one.
+func (e CanceledError) MarshalJSON() ([]byte, error) { return json.Marshal(newJSONError(e)) }
+
+func (e errInvalidInput) MarshalJSON() ([]byte, error) { return json.Marshal(newJSONError(e)) }
+
+func (e errInvalidGroupOption) MarshalJSON() ([]byte, error) { return json.Marshal(newJSONError(e)) }
+
+func (e errProvide) MarshalJSON() ([]byte, error) { return json.Marshal(newJSONError(e)) }
+
+func (e errConstructorFailed) MarshalJSON() ([]byte, error) { return json.Marshal(newJSONError(e)) }
+
+func (e errRetriesFailed) MarshalJSON() ([]byte, error) { return json.Marshal(newJSONError(e)) }
+
+func (e errArgumentsFailed) MarshalJSON() ([]byte, error) { return json.Marshal(newJSONError(e)) }
+
+func (e errDependenciesFailed) MarshalJSON() ([]byte, error) { return json.Marshal(newJSONError(e)) }
+
+func (e errMissingDependencies) MarshalJSON() ([]byte, error) { return json.Marshal(newJSONError(e)) }
+
+func (e errParamSingleFailed) MarshalJSON() ([]byte, error) { return json.Marshal(newJSONError(e)) }
+
+func (e errParamGroupFailed) MarshalJSON() ([]byte, error) { return json.Marshal(newJSONError(e)) }
+
+func (e errMissingTypes) MarshalJSON() ([]byte, error) { return json.Marshal(newJSONError(e)) }
+
+func (e errCycleDetected) MarshalJSON() ([]byte, error) { return json.Marshal(newJSONError(e)) }
+
+func (e errMissingDependencyPath) MarshalJSON() ([]byte, error) {
+	return json.Marshal(newJSONError(e))
+}
+
+func (e errVerifyFailed) MarshalJSON() ([]byte, error) { return json.Marshal(newJSONError(e)) }
+
+func (e errCleanupFailed) MarshalJSON() ([]byte, error) { return json.Marshal(newJSONError(e)) }
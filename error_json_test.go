@@ -0,0 +1,299 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/dig"
+	"go.uber.org/dig/internal/digtest"
+)
+
+type testJSONKey struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Group string `json:"group"`
+}
+
+type testJSONError struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+	Func    *struct {
+		Name    string `json:"name"`
+		Package string `json:"package"`
+		File    string `json:"file"`
+		Line    int    `json:"line"`
+	} `json:"func"`
+	Key     *testJSONKey `json:"key"`
+	Missing []struct {
+		Key         testJSONKey   `json:"key"`
+		Suggestions []testJSONKey `json:"suggestions"`
+	} `json:"missing"`
+	Path []struct {
+		Key  *testJSONKey `json:"key"`
+		Func struct {
+			Name string `json:"name"`
+		} `json:"func"`
+	} `json:"path"`
+	Attempts []string         `json:"attempts"`
+	Panic    string           `json:"panic"`
+	Timeout  string           `json:"timeout"`
+	Cause    *testJSONError   `json:"cause"`
+	Errors   []*testJSONError `json:"errors"`
+}
+
+// kinds lists the kinds of the chain of errors starting at e.
+func (e *testJSONError) kinds() []string {
+	var kinds []string
+	for ; e != nil; e = e.Cause {
+		kinds = append(kinds, e.Kind)
+	}
+	return kinds
+}
+
+func marshalTestError(t *testing.T, err error) *testJSONError {
+	t.Helper()
+
+	b, merr := json.Marshal(err)
+	require.NoError(t, merr)
+
+	var je testJSONError
+	require.NoError(t, json.Unmarshal(b, &je), "invalid JSON: %s", b)
+	return &je
+}
+
+func TestErrorJSON(t *testing.T) {
+	t.Parallel()
+
+	type A struct{}
+	type B struct{}
+
+	t.Run("constructor failed", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() (*A, error) { return nil, errors.New("great sadness") })
+		err := c.Invoke(func(*A) {})
+		require.Error(t, err)
+
+		je := marshalTestError(t, err)
+		assert.Equal(t, []string{
+			"argumentsFailed", "paramFailed", "constructorFailed", "error",
+		}, je.kinds())
+
+		require.NotNil(t, je.Func)
+		assert.Equal(t, "TestErrorJSON.func1.2", je.Func.Name)
+		assert.Equal(t, "go.uber.org/dig_test", je.Func.Package)
+		assert.NotZero(t, je.Func.Line)
+		assert.Contains(t, je.Message, "could not build arguments for function")
+		assert.NotContains(t, je.Message, "great sadness",
+			"message must not include the wrapped errors")
+
+		param := je.Cause
+		assert.Equal(t, &testJSONKey{Type: "*dig_test.A"}, param.Key)
+		assert.Equal(t, "failed to build *dig_test.A", param.Message)
+
+		ctor := param.Cause
+		require.NotNil(t, ctor.Func)
+		assert.Equal(t, "TestErrorJSON.func1.1", ctor.Func.Name)
+		assert.Equal(t, "great sadness", ctor.Cause.Message)
+	})
+
+	t.Run("missing types", func(t *testing.T) {
+		t.Parallel()
+
+		type in struct {
+			dig.In
+
+			A A `name:"a"`
+			B *B
+		}
+
+		c := digtest.New(t)
+		c.RequireProvide(func() *A { return &A{} }, dig.Name("a"))
+		err := c.Invoke(func(in) {})
+		require.Error(t, err)
+
+		je := marshalTestError(t, err)
+		assert.Equal(t, []string{"missingDependencies", "missingTypes"}, je.kinds())
+
+		missing := je.Cause.Missing
+		require.Len(t, missing, 2)
+		assert.Equal(t, testJSONKey{Type: "dig_test.A", Name: "a"}, missing[0].Key)
+		assert.Equal(t, []testJSONKey{{Type: "*dig_test.A", Name: "a"}}, missing[0].Suggestions)
+		assert.Equal(t, testJSONKey{Type: "*dig_test.B"}, missing[1].Key)
+		assert.Empty(t, missing[1].Suggestions)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func(*B) *A { return &A{} })
+		err := c.Provide(func(*A) *B { return &B{} })
+		require.Error(t, err)
+		require.True(t, dig.IsCycleDetected(err))
+
+		je := marshalTestError(t, err)
+		assert.Equal(t, []string{"provideFailed", "invalidInput", "cycleDetected"}, je.kinds())
+
+		cycle := je.Cause.Cause
+		require.Len(t, cycle.Path, 3)
+		assert.Equal(t, &testJSONKey{Type: "func(*dig_test.B) *dig_test.A"}, cycle.Path[0].Key)
+		assert.Equal(t, "TestErrorJSON.func3.1", cycle.Path[0].Func.Name)
+		assert.Equal(t, &testJSONKey{Type: "func(*dig_test.A) *dig_test.B"}, cycle.Path[1].Key)
+		assert.Equal(t, "TestErrorJSON.func3.2", cycle.Path[1].Func.Name)
+		assert.Equal(t, cycle.Path[0], cycle.Path[2])
+	})
+
+	t.Run("verify", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func(*B) *A { return &A{} })
+		err := c.Verify(func(*A) {})
+		require.Error(t, err)
+
+		je := marshalTestError(t, err)
+		assert.Equal(t, "verifyFailed", je.Kind)
+		require.Len(t, je.Errors, 1)
+
+		missing := je.Errors[0]
+		assert.Equal(t, []string{"missingDependencyPath", "missingTypes"}, missing.kinds())
+		require.Len(t, missing.Path, 2)
+		assert.Nil(t, missing.Path[0].Key)
+		assert.Equal(t, &testJSONKey{Type: "*dig_test.A"}, missing.Path[1].Key)
+	})
+
+	t.Run("retries", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() (*A, error) { return nil, errors.New("great sadness") },
+			dig.Retry(dig.RetryPolicy{MaxAttempts: 2}))
+		err := c.Invoke(func(*A) {})
+		require.Error(t, err)
+
+		retries := marshalTestError(t, err).Cause.Cause
+		assert.Equal(t, "retriesFailed", retries.Kind)
+		assert.Equal(t, []string{"great sadness"}, retries.Attempts)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t, dig.RecoverFromPanics())
+		c.RequireProvide(func() *A { panic("great sadness") })
+		err := c.Invoke(func(*A) {})
+		require.Error(t, err)
+
+		panicked := marshalTestError(t, err).Cause.Cause
+		assert.Equal(t, "panic", panicked.Kind)
+		assert.Equal(t, "great sadness", panicked.Panic)
+		require.NotNil(t, panicked.Func)
+		assert.Equal(t, "TestErrorJSON.func6.1", panicked.Func.Name)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Parallel()
+
+		je := marshalTestError(t, dig.CanceledError{
+			Location: dig.Location{Name: "NewA", Package: "example.com/a", File: "a.go", Line: 42},
+			Timeout:  time.Second,
+			Err:      errors.New("great sadness"),
+		})
+		assert.Equal(t, "canceled", je.Kind)
+		assert.Equal(t, "1s", je.Timeout)
+		require.NotNil(t, je.Func)
+		assert.Equal(t, "NewA", je.Func.Name)
+		assert.Equal(t, "example.com/a", je.Func.Package)
+		assert.Equal(t, "a.go", je.Func.File)
+		assert.Equal(t, 42, je.Func.Line)
+	})
+
+	t.Run("key", func(t *testing.T) {
+		t.Parallel()
+
+		b, err := json.Marshal(dig.Key{Type: reflect.TypeOf(A{}), Group: "as"})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"type": "dig_test.A", "group": "as"}`, string(b))
+
+		b, err = json.Marshal(dig.Key{})
+		require.NoError(t, err)
+		assert.Equal(t, "null", string(b))
+	})
+
+	t.Run("dependency path", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() (*A, error) { return nil, errors.New("great sadness") })
+		c.RequireProvide(func(*A) *B { return &B{} })
+		err := c.Invoke(func(*B) {})
+		require.Error(t, err)
+
+		path := dig.DependencyPath(err)
+		require.Len(t, path, 3)
+		b, merr := json.Marshal(path)
+		require.NoError(t, merr)
+
+		var entries []struct {
+			Location struct{ Name string }
+			Key      *testJSONKey
+		}
+		require.NoError(t, json.Unmarshal(b, &entries), "invalid JSON: %s", b)
+		require.Len(t, entries, 3)
+		assert.Equal(t, "TestErrorJSON.func9.3", entries[0].Location.Name)
+		assert.Equal(t, &testJSONKey{Type: "*dig_test.B"}, entries[0].Key)
+		assert.Equal(t, &testJSONKey{Type: "*dig_test.A"}, entries[1].Key)
+		assert.Nil(t, entries[2].Key, "the failed constructor requested nothing")
+	})
+
+	t.Run("cleanup failed", func(t *testing.T) {
+		t.Parallel()
+
+		errCleanup := errors.New("cleanup failed")
+		c := digtest.New(t)
+		c.RequireProvide(func() (*A, func() error) {
+			return &A{}, func() error { return errCleanup }
+		}, dig.Cleanup())
+		c.RequireProvide(func() (*B, error) { return nil, errors.New("great sadness") })
+		err := c.Invoke(func(*A, *B) {})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errCleanup)
+		assert.EqualError(t, dig.RootCause(err), "great sadness")
+
+		je := marshalTestError(t, err)
+		assert.Equal(t, "cleanupFailed", je.Kind)
+		assert.Equal(t, "1 cleanup function failed:\n[1] cleanup failed", je.Message)
+		require.Len(t, je.Errors, 1)
+		assert.Equal(t, "cleanup failed", je.Errors[0].Message)
+		assert.Equal(t, []string{
+			"cleanupFailed", "argumentsFailed", "paramFailed", "constructorFailed", "error",
+		}, je.kinds())
+	})
+}
@@ -0,0 +1,356 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Key identifies a value in the container: its type, and either the name
+// it was provided with or the value group it belongs to.
+type Key struct {
+	// Type of the value.
+	Type reflect.Type
+
+	// Name of the value, if it is a named value.
+	Name string
+
+	// Group of the value, if it is part of a value group.
+	Group string
+}
+
+func newKey(k key) Key {
+	return Key{Type: k.t, Name: k.name, Group: k.group}
+}
+
+// String returns the key in the same format as error messages:
+//
+//	*bytes.Buffer
+//	*bytes.Buffer[name="buf"]
+//	*bytes.Buffer[group="bufs"]
+//
+// It returns an empty string for the zero Key.
+func (k Key) String() string {
+	if k.Type == nil {
+		return ""
+	}
+	return key{t: k.Type, name: k.Name, group: k.Group}.String()
+}
+
+// PathEntry is one step of the dependency path reported by
+// [DependencyPath].
+type PathEntry struct {
+	// Location of the invoked function, constructor or decorator.
+	Location Location
+
+	// Key of the dependency of the function that failed to build.
+	// Its Type is nil on the last entry of the path, whose function
+	// failed itself.
+	Key Key
+}
+
+// errPathElement is implemented by errors that make up a step of the
+// dependency path from an invoked function down to the root cause.
+type errPathElement interface {
+	updatePath([]PathEntry) []PathEntry
+}
+
+// withFunc adds the function at loc to the end of the path.
+func withFunc(path []PathEntry, loc Location) []PathEntry {
+	return append(path, PathEntry{Location: loc})
+}
+
+// withKey records k as the dependency that the last function of the path
+// failed to build.
+func withKey(path []PathEntry, k key) []PathEntry {
+	if len(path) == 0 || path[len(path)-1].Key.Type != nil {
+		path = append(path, PathEntry{})
+	}
+	path[len(path)-1].Key = newKey(k)
+	return path
+}
+
+func (e errConstructorFailed) updatePath(path []PathEntry) []PathEntry {
+	return withFunc(path, newLocation(e.Func))
+}
+
+func (e errArgumentsFailed) updatePath(path []PathEntry) []PathEntry {
+	return withFunc(path, newLocation(e.Func))
+}
+
+func (e errMissingDependencies) updatePath(path []PathEntry) []PathEntry {
+	return withFunc(path, newLocation(e.Func))
+}
+
+func (e errParamSingleFailed) updatePath(path []PathEntry) []PathEntry {
+	return withKey(path, e.Key)
+}
+
+func (e errParamGroupFailed) updatePath(path []PathEntry) []PathEntry {
+	return withKey(path, e.Key)
+}
+
+func (e PanicError) updatePath(path []PathEntry) []PathEntry {
+	return withFunc(path, newLocation(e.fn))
+}
+
+func (e CanceledError) updatePath(path []PathEntry) []PathEntry {
+	return withFunc(path, e.Location)
+}
+
+// errFailedFunc is implemented by errors that report the failure of a
+// function called by the container.
+type errFailedFunc interface {
+	failedFunc() (Location, bool)
+}
+
+func (e errConstructorFailed) failedFunc() (Location, bool) {
+	return newLocation(e.Func), true
+}
+
+func (e PanicError) failedFunc() (Location, bool) {
+	return newLocation(e.fn), true
+}
+
+func (e CanceledError) failedFunc() (Location, bool) {
+	// Functions that were stopped before they were called didn't fail.
+	return e.Location, e.Timeout > 0
+}
+
+// DependencyPath returns the chain of functions that led to err: the
+// invoked function first, followed by the constructors of the
+// dependencies that failed to build, down to the function that failed
+// itself. Each entry holds the key of the dependency that the function
+// could not get.
+//
+//	err := c.Invoke(func(*Server) { ... })
+//	for _, e := range dig.DependencyPath(err) {
+//		fmt.Println(e.Location, e.Key)
+//	}
+//
+// If several dependencies failed, as reported with [CollectErrors], the
+// path leads to the first of them. Use [ErrorTree] to see all of them.
+//
+// DependencyPath returns nil if err did not come from building the
+// dependencies of a function.
+func DependencyPath(err error) []PathEntry {
+	var path []PathEntry
+	for ; err != nil; err = unwrapFirst(err) {
+		if pe, ok := err.(errPathElement); ok {
+			path = pe.updatePath(path)
+		}
+	}
+	return path
+}
+
+// MissingTypes returns the keys of the values that were missing from the
+// container, if err was caused by missing dependencies.
+func MissingTypes(err error) []Key {
+	var keys []Key
+	seen := make(map[Key]struct{})
+	walkErrors(err, func(err error) {
+		emt, ok := err.(errMissingTypes)
+		if !ok {
+			return
+		}
+		for _, mt := range emt {
+			k := newKey(mt.Key)
+			if _, ok := seen[k]; !ok {
+				seen[k] = struct{}{}
+				keys = append(keys, k)
+			}
+		}
+	})
+	return keys
+}
+
+// FailedConstructor returns the location of the function that err
+// originated from, if it was caused by a constructor or decorator that
+// returned an error, panicked with the [RecoverFromPanics] option set, or
+// did not return within its [Timeout]. If several dependencies failed, it
+// reports the function of the first of them.
+func FailedConstructor(err error) (Location, bool) {
+	var (
+		loc   Location
+		found bool
+	)
+	// Keep the deepest failure: the others were caused by it.
+	for ; err != nil; err = unwrapFirst(err) {
+		if fe, ok := err.(errFailedFunc); ok {
+			if l, ok := fe.failedFunc(); ok {
+				loc, found = l, true
+			}
+		}
+	}
+	return loc, found
+}
+
+// unwrapFirst is like errors.Unwrap, but returns the first of the errors
+// wrapped by a multi-error.
+func unwrapFirst(err error) error {
+	if me, ok := err.(interface{ Unwrap() []error }); ok {
+		if errs := me.Unwrap(); len(errs) > 0 {
+			return errs[0]
+		}
+		return nil
+	}
+	return errors.Unwrap(err)
+}
+
+// walkErrors calls fn with err and every error it wraps, depth first,
+// including all the errors wrapped by multi-errors.
+func walkErrors(err error, fn func(error)) {
+	for ; err != nil; err = errors.Unwrap(err) {
+		fn(err)
+		if me, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, e := range me.Unwrap() {
+				walkErrors(e, fn)
+			}
+			return
+		}
+	}
+}
+
+// ErrorTree renders err as a compact tree of the dependency path that led
+// to it, with the root cause at the bottom. It's an alternative to the
+// nested output of %+v.
+//
+//	"example.com/app".main.func1 (app/main.go:42)
+//	└─ *app.Server: "example.com/app".NewServer (app/server.go:17)
+//	   └─ *sql.DB: "example.com/app".NewDB (app/db.go:23)
+//	      └─ dial tcp: connection refused
+//
+// If several dependencies failed, as reported with [CollectErrors], the
+// tree branches out to each of them.
+//
+//	"example.com/app".main.func1 (app/main.go:42)
+//	├─ *sql.DB: "example.com/app".NewDB (app/db.go:23)
+//	│  └─ dial tcp: connection refused
+//	└─ *app.Config: "example.com/app".LoadConfig (app/config.go:12)
+//	   └─ open config.yaml: no such file or directory
+//
+// Errors that did not come from building dependencies are rendered as
+// their message alone.
+func ErrorTree(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var root errorTreeNode
+	root.add(err)
+
+	var b strings.Builder
+	for i, n := range root.children {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(n.text)
+		n.writeChildren(&b, "")
+	}
+	return b.String()
+}
+
+// errorTreeNode is a line of the output of ErrorTree.
+type errorTreeNode struct {
+	text     string
+	children []*errorTreeNode
+}
+
+// add adds the lines that describe err below n.
+func (n *errorTreeNode) add(err error) {
+	var (
+		path     []PathEntry
+		branches []error
+		cause    error
+	)
+	// Follow the chain down to the root cause like RootCause, or to the
+	// errors of multiple dependencies.
+	for ; err != nil; err = errors.Unwrap(err) {
+		cause = err
+		if pe, ok := err.(errPathElement); ok {
+			path = pe.updatePath(path)
+		}
+		if me, ok := err.(interface{ Unwrap() []error }); ok {
+			branches = me.Unwrap()
+			break
+		}
+		if _, ok := err.(Error); !ok {
+			break
+		}
+	}
+
+	line := func(parts ...string) {
+		var nonEmpty []string
+		for _, p := range parts {
+			if p != "" {
+				nonEmpty = append(nonEmpty, p)
+			}
+		}
+		child := &errorTreeNode{text: strings.Join(nonEmpty, ": ")}
+		n.children = append(n.children, child)
+		n = child
+	}
+
+	var prev string // key needed by the previous function
+	for _, e := range path {
+		var loc string
+		if e.Location != (Location{}) {
+			loc = e.Location.String()
+		}
+		if prev != "" || loc != "" {
+			line(prev, loc)
+		}
+
+		prev = ""
+		if e.Key.Type != nil {
+			prev = e.Key.String()
+		}
+	}
+	if prev != "" {
+		line(prev)
+	}
+
+	if branches == nil {
+		line(fmt.Sprint(cause))
+		return
+	}
+	for _, b := range branches {
+		n.add(b)
+	}
+}
+
+// writeChildren writes the lines below n, each starting with prefix.
+func (n *errorTreeNode) writeChildren(b *strings.Builder, prefix string) {
+	for i, c := range n.children {
+		branch, indent := "├─ ", "│  "
+		if i == len(n.children)-1 {
+			branch, indent = "└─ ", "   "
+		}
+		b.WriteString("\n")
+		b.WriteString(prefix)
+		b.WriteString(branch)
+		b.WriteString(c.text)
+		c.writeChildren(b, prefix+indent)
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/dig"
+	"go.uber.org/dig/internal/digtest"
+)
+
+func TestErrorPath(t *testing.T) {
+	t.Parallel()
+
+	type A struct{}
+	type B struct{}
+	type C struct{}
+
+	names := func(path []dig.PathEntry) []string {
+		var ns []string
+		for _, e := range path {
+			ns = append(ns, e.Location.Name)
+		}
+		return ns
+	}
+
+	t.Run("constructor failed", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() (*A, error) { return nil, errors.New("great sadness") })
+		c.RequireProvide(func(*A) *B { return &B{} })
+		err := c.Invoke(func(*B) {})
+		require.Error(t, err)
+
+		path := dig.DependencyPath(err)
+		assert.Equal(t, []string{
+			"TestErrorPath.func2.3",
+			"TestErrorPath.func2.2",
+			"TestErrorPath.func2.1",
+		}, names(path))
+		assert.Equal(t, dig.Key{Type: reflect.TypeOf(&B{})}, path[0].Key)
+		assert.Equal(t, dig.Key{Type: reflect.TypeOf(&A{})}, path[1].Key)
+		assert.Nil(t, path[2].Key.Type)
+
+		loc, ok := dig.FailedConstructor(err)
+		require.True(t, ok)
+		assert.Equal(t, path[2].Location, loc)
+		assert.Empty(t, dig.MissingTypes(err))
+
+		assert.Equal(t, strings.Join([]string{
+			path[0].Location.String(),
+			"└─ *dig_test.B: " + path[1].Location.String(),
+			"   └─ *dig_test.A: " + path[2].Location.String(),
+			"      └─ great sadness",
+		}, "\n"), dig.ErrorTree(err))
+	})
+
+	t.Run("missing types", func(t *testing.T) {
+		t.Parallel()
+
+		type in struct {
+			dig.In
+
+			A *A `name:"a"`
+			C *C
+		}
+
+		c := digtest.New(t)
+		c.RequireProvide(func(in) *B { return &B{} })
+		err := c.Invoke(func(*B) {})
+		require.Error(t, err)
+
+		assert.Equal(t, []dig.Key{
+			{Type: reflect.TypeOf(&A{}), Name: "a"},
+			{Type: reflect.TypeOf(&C{})},
+		}, dig.MissingTypes(err))
+
+		path := dig.DependencyPath(err)
+		assert.Equal(t, []string{
+			"TestErrorPath.func3.2",
+			"TestErrorPath.func3.1",
+		}, names(path))
+		assert.Equal(t, dig.Key{Type: reflect.TypeOf(&B{})}, path[0].Key)
+		assert.Nil(t, path[1].Key.Type)
+
+		_, ok := dig.FailedConstructor(err)
+		assert.False(t, ok)
+
+		tree := dig.ErrorTree(err)
+		assert.Contains(t, tree, "└─ *dig_test.B: "+path[1].Location.String())
+		assert.Contains(t, tree, `   └─ missing types: *dig_test.A[name="a"]; *dig_test.C`)
+	})
+
+	t.Run("value groups", func(t *testing.T) {
+		t.Parallel()
+
+		type in struct {
+			dig.In
+
+			As []*A `group:"as"`
+		}
+		type out struct {
+			dig.Out
+
+			A *A `group:"as"`
+		}
+
+		c := digtest.New(t)
+		c.RequireProvide(func() (out, error) { return out{}, errors.New("great sadness") })
+		err := c.Invoke(func(in) {})
+		require.Error(t, err)
+
+		path := dig.DependencyPath(err)
+		assert.Equal(t, []string{
+			"TestErrorPath.func4.2",
+			"TestErrorPath.func4.1",
+		}, names(path))
+		assert.Equal(t, dig.Key{Type: reflect.TypeOf(&A{}), Group: "as"}, path[0].Key)
+		assert.Equal(t, `*dig_test.A[group="as"]`, path[0].Key.String())
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t, dig.RecoverFromPanics())
+		c.RequireProvide(func() *A { panic("great sadness") })
+		err := c.Invoke(func(*A) {})
+		require.Error(t, err)
+
+		loc, ok := dig.FailedConstructor(err)
+		require.True(t, ok)
+		assert.Equal(t, "TestErrorPath.func5.1", loc.Name)
+		assert.Equal(t, []string{
+			"TestErrorPath.func5.2",
+			"TestErrorPath.func5.1",
+		}, names(dig.DependencyPath(err)))
+	})
+
+	t.Run("errors from elsewhere", func(t *testing.T) {
+		t.Parallel()
+
+		err := errors.New("great sadness")
+		assert.Empty(t, dig.DependencyPath(err))
+		assert.Empty(t, dig.MissingTypes(err))
+		_, ok := dig.FailedConstructor(err)
+		assert.False(t, ok)
+		assert.Equal(t, "great sadness", dig.ErrorTree(err))
+		assert.Empty(t, dig.ErrorTree(nil))
+
+		c := digtest.New(t)
+		err = c.Invoke(func() error { return err })
+		assert.Empty(t, dig.DependencyPath(err))
+		assert.Equal(t, "great sadness", dig.ErrorTree(err))
+	})
+
+	t.Run("Key", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Empty(t, dig.Key{}.String())
+		assert.Equal(t, `*bytes.Buffer[name="buf"]`,
+			dig.Key{Type: reflect.TypeOf(new(bytes.Buffer)), Name: "buf"}.String())
+	})
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+//go:build go1.21
+
+package dig
+
+import "log/slog"
+
+func (je *jsonError) logValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("kind", je.Kind),
+		slog.String("message", je.Message),
+	}
+	if f := je.Func; f != nil {
+		attrs = append(attrs, slog.Group("func",
+			slog.String("name", f.Name),
+			slog.String("package", f.Package),
+			slog.String("file", f.File),
+			slog.Int("line", f.Line),
+		))
+	}
+	if k := je.Key; k != nil {
+		keyAttrs := []any{slog.String("type", k.Type)}
+		if k.Name != "" {
+			keyAttrs = append(keyAttrs, slog.String("name", k.Name))
+		}
+		if k.Group != "" {
+			keyAttrs = append(keyAttrs, slog.String("group", k.Group))
+		}
+		attrs = append(attrs, slog.Group("key", keyAttrs...))
+	}
+	if len(je.Missing) > 0 {
+		attrs = append(attrs, slog.Any("missing", je.Missing))
+	}
+	if len(je.Path) > 0 {
+		attrs = append(attrs, slog.Any("path", je.Path))
+	}
+	if je.Scope != "" {
+		attrs = append(attrs, slog.String("scope", je.Scope))
+	}
+	if len(je.Attempts) > 0 {
+		attrs = append(attrs, slog.Any("attempts", je.Attempts))
+	}
+	if je.Panic != "" {
+		attrs = append(attrs, slog.String("panic", je.Panic))
+	}
+	if je.Timeout != "" {
+		attrs = append(attrs, slog.String("timeout", je.Timeout))
+	}
+	if je.Cause != nil {
+		attrs = append(attrs, slog.Attr{Key: "cause", Value: je.Cause.logValue()})
+	}
+	if len(je.Errors) > 0 {
+		attrs = append(attrs, slog.Any("errors", je.Errors))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue reports the panic as a group with the location of the function
+// that panicked and the value it panicked with.
+func (e PanicError) LogValue() slog.Value { return newJSONError(e).logValue() }
+
+// LogValue reports the error as a group with the location of the function
+// that was stopped and its timeout, if it had one.
+func (e CanceledError) LogValue() slog.Value { return newJSONError(e).logValue() }
+
+func (e errInvalidInput) LogValue() slog.Value { return newJSONError(e).logValue() }
+
+func (e errInvalidGroupOption) LogValue() slog.Value { return newJSONError(e).logValue() }
+
+func (e errProvide) LogValue() slog.Value { return newJSONError(e).logValue() }
+
+func (e errConstructorFailed) LogValue() slog.Value { return newJSONError(e).logValue() }
+
+func (e errRetriesFailed) LogValue() slog.Value { return newJSONError(e).logValue() }
+
+func (e errArgumentsFailed) LogValue() slog.Value { return newJSONError(e).logValue() }
+
+func (e errDependenciesFailed) LogValue() slog.Value { return newJSONError(e).logValue() }
+
+func (e errMissingDependencies) LogValue() slog.Value { return newJSONError(e).logValue() }
+
+func (e errParamSingleFailed) LogValue() slog.Value { return newJSONError(e).logValue() }
+
+func (e errParamGroupFailed) LogValue() slog.Value { return newJSONError(e).logValue() }
+
+func (e errMissingTypes) LogValue() slog.Value { return newJSONError(e).logValue() }
+
+func (e errCycleDetected) LogValue() slog.Value { return newJSONError(e).logValue() }
+
+func (e errMissingDependencyPath) LogValue() slog.Value { return newJSONError(e).logValue() }
+
+func (e errVerifyFailed) LogValue() slog.Value { return newJSONError(e).logValue() }
+
+func (e errCleanupFailed) LogValue() slog.Value { return newJSONError(e).logValue() }
@@ -0,0 +1,371 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"go.uber.org/dig/internal/digreflect"
+)
+
+// EventHandler handles the events emitted by a Container. See
+// WithEventHandler.
+type EventHandler interface {
+	HandleEvent(Event)
+}
+
+// EventHandlerFunc is an EventHandler implemented by a function.
+type EventHandlerFunc func(Event)
+
+// HandleEvent calls f with the event.
+func (f EventHandlerFunc) HandleEvent(e Event) { f(e) }
+
+// Event is an event emitted by a Container. It's one of the following
+// types:
+//
+//   - Provided
+//   - ProvideFailed
+//   - Decorated
+//   - Invoking
+//   - Invoked
+//   - ConstructorStart
+//   - ConstructorEnd
+//   - DecoratorStart
+//   - DecoratorEnd
+//   - ScopeCreated
+type Event interface {
+	// logEntry returns the message and attributes the event is logged
+	// with.
+	logEntry() eventEntry
+}
+
+// WithEventHandler is an Option that sends the events emitted by the
+// Container and all its Scopes to the given EventHandler. If specified
+// multiple times, events are sent to every handler in the order they were
+// specified.
+//
+// Events are emitted synchronously, from the goroutine doing the work they
+// describe. With ParallelConstruction, the handler may be called from
+// multiple goroutines at the same time. The handler may call into the
+// Container, except from the ConstructorStart and DecoratorStart events of
+// constructors and decorators that are building the values it requests.
+//
+//	c := dig.New(dig.WithEventHandler(dig.NewTextEventLogger(os.Stderr)))
+func WithEventHandler(h EventHandler) Option {
+	return withEventHandlerOption{h}
+}
+
+type withEventHandlerOption struct{ h EventHandler }
+
+func (o withEventHandlerOption) String() string {
+	return fmt.Sprintf("WithEventHandler(%T)", o.h)
+}
+
+func (o withEventHandlerOption) applyOption(c *Container) {
+	c.scope.eventHandlers = append(c.scope.eventHandlers, o.h)
+}
+
+// funcLocation returns the location of the function f, or an empty
+// Location if f is not a function.
+func funcLocation(f interface{}) Location {
+	if t := reflect.TypeOf(f); t == nil || t.Kind() != reflect.Func {
+		return Location{}
+	}
+	return newLocation(digreflect.InspectFunc(f))
+}
+
+// emitting reports whether the Scope has event handlers. Events need not be
+// created if it doesn't.
+func (s *Scope) emitting() bool {
+	return len(s.eventHandlers) > 0
+}
+
+// emit sends the event to the event handlers of the Scope.
+func (s *Scope) emit(e Event) {
+	for _, h := range s.eventHandlers {
+		h.HandleEvent(e)
+	}
+}
+
+// Provided is emitted when a constructor was provided to a Scope.
+type Provided struct {
+	// Location of the constructor.
+	Location Location
+
+	// Name of the Scope the constructor was provided to.
+	Scope string
+
+	// Values produced by the constructor.
+	Outputs []*Output
+}
+
+// ProvideFailed is emitted when a constructor could not be provided to a
+// Scope.
+type ProvideFailed struct {
+	// Location of the constructor.
+	Location Location
+
+	// Name of the Scope the constructor was provided to.
+	Scope string
+
+	// Error returned by Provide.
+	Err error
+}
+
+// Decorated is emitted when a decorator was registered with a Scope, or
+// failed to be.
+type Decorated struct {
+	// Location of the decorator.
+	Location Location
+
+	// Name of the Scope the decorator was registered with.
+	Scope string
+
+	// Values produced by the decorator, if it was registered.
+	Outputs []*Output
+
+	// Error returned by Decorate, if any.
+	Err error
+}
+
+// Invoking is emitted before a function is invoked, before its
+// dependencies are built.
+type Invoking struct {
+	// Location of the function.
+	Location Location
+
+	// Name of the Scope the function is invoked in.
+	Scope string
+
+	// Parameters of the function.
+	Inputs []*Input
+}
+
+// Invoked is emitted after a function was invoked, or failed to be.
+type Invoked struct {
+	// Location of the function.
+	Location Location
+
+	// Name of the Scope the function was invoked in.
+	Scope string
+
+	// Parameters of the function.
+	Inputs []*Input
+
+	// Time spent invoking the function, building its dependencies
+	// included.
+	Runtime time.Duration
+
+	// Error returned by Invoke, if any.
+	Err error
+}
+
+// ConstructorStart is emitted when a constructor is about to be called,
+// after its dependencies were built.
+type ConstructorStart struct {
+	// Location of the constructor.
+	Location Location
+
+	// Name of the Scope the constructor is called for.
+	Scope string
+
+	// Values produced by the constructor.
+	Outputs []*Output
+}
+
+// ConstructorEnd is emitted after a constructor returned.
+type ConstructorEnd struct {
+	// Location of the constructor.
+	Location Location
+
+	// Name of the Scope the constructor was called for.
+	Scope string
+
+	// Values produced by the constructor.
+	Outputs []*Output
+
+	// Time spent in the constructor.
+	Runtime time.Duration
+
+	// Error returned by the constructor, if any.
+	Err error
+}
+
+// DecoratorStart is emitted when a decorator is about to be called, after
+// its dependencies were built.
+type DecoratorStart struct {
+	// Location of the decorator.
+	Location Location
+
+	// Name of the Scope the decorator was registered with.
+	Scope string
+
+	// Values produced by the decorator.
+	Outputs []*Output
+}
+
+// DecoratorEnd is emitted after a decorator returned.
+type DecoratorEnd struct {
+	// Location of the decorator.
+	Location Location
+
+	// Name of the Scope the decorator was registered with.
+	Scope string
+
+	// Values produced by the decorator.
+	Outputs []*Output
+
+	// Time spent in the decorator.
+	Runtime time.Duration
+
+	// Error returned by the decorator, if any.
+	Err error
+}
+
+// ScopeCreated is emitted when a child Scope was created.
+type ScopeCreated struct {
+	// Name of the new Scope.
+	Name string
+
+	// Name of the parent of the new Scope.
+	Parent string
+}
+
+// eventEntry is the representation of an event in logs.
+type eventEntry struct {
+	Msg   string
+	Attrs []eventAttr
+
+	// Err is the error carried by the event, if any. Events with errors
+	// are logged at the error level.
+	Err error
+}
+
+// eventAttr is an attribute of a logged event. Values are strings, string
+// slices or durations.
+type eventAttr struct {
+	Key   string
+	Value interface{}
+}
+
+func locationAttrs(l Location, scope string) []eventAttr {
+	return []eventAttr{
+		{"function", l.Package + "." + l.Name},
+		{"location", fmt.Sprintf("%v:%v", l.File, l.Line)},
+		{"scope", scope},
+	}
+}
+
+func outputsAttr(outputs []*Output) eventAttr {
+	vs := make([]string, len(outputs))
+	for i, o := range outputs {
+		vs[i] = o.String()
+	}
+	return eventAttr{"outputs", vs}
+}
+
+func inputsAttr(inputs []*Input) eventAttr {
+	vs := make([]string, len(inputs))
+	for i, in := range inputs {
+		vs[i] = in.String()
+	}
+	return eventAttr{"inputs", vs}
+}
+
+func (e Provided) logEntry() eventEntry {
+	return eventEntry{
+		Msg:   "provided",
+		Attrs: append(locationAttrs(e.Location, e.Scope), outputsAttr(e.Outputs)),
+	}
+}
+
+func (e ProvideFailed) logEntry() eventEntry {
+	return eventEntry{
+		Msg:   "provide failed",
+		Attrs: locationAttrs(e.Location, e.Scope),
+		Err:   e.Err,
+	}
+}
+
+func (e Decorated) logEntry() eventEntry {
+	return eventEntry{
+		Msg:   "decorated",
+		Attrs: append(locationAttrs(e.Location, e.Scope), outputsAttr(e.Outputs)),
+		Err:   e.Err,
+	}
+}
+
+func (e Invoking) logEntry() eventEntry {
+	return eventEntry{
+		Msg:   "invoking",
+		Attrs: append(locationAttrs(e.Location, e.Scope), inputsAttr(e.Inputs)),
+	}
+}
+
+func (e Invoked) logEntry() eventEntry {
+	return eventEntry{
+		Msg: "invoked",
+		Attrs: append(locationAttrs(e.Location, e.Scope),
+			inputsAttr(e.Inputs), eventAttr{"runtime", e.Runtime}),
+		Err: e.Err,
+	}
+}
+
+func (e ConstructorStart) logEntry() eventEntry {
+	return eventEntry{
+		Msg:   "constructor started",
+		Attrs: append(locationAttrs(e.Location, e.Scope), outputsAttr(e.Outputs)),
+	}
+}
+
+func (e ConstructorEnd) logEntry() eventEntry {
+	return eventEntry{
+		Msg: "constructor finished",
+		Attrs: append(locationAttrs(e.Location, e.Scope),
+			outputsAttr(e.Outputs), eventAttr{"runtime", e.Runtime}),
+		Err: e.Err,
+	}
+}
+
+func (e DecoratorStart) logEntry() eventEntry {
+	return eventEntry{
+		Msg:   "decorator started",
+		Attrs: append(locationAttrs(e.Location, e.Scope), outputsAttr(e.Outputs)),
+	}
+}
+
+func (e DecoratorEnd) logEntry() eventEntry {
+	return eventEntry{
+		Msg: "decorator finished",
+		Attrs: append(locationAttrs(e.Location, e.Scope),
+			outputsAttr(e.Outputs), eventAttr{"runtime", e.Runtime}),
+		Err: e.Err,
+	}
+}
+
+func (e ScopeCreated) logEntry() eventEntry {
+	return eventEntry{
+		Msg:   "scope created",
+		Attrs: []eventAttr{{"scope", e.Name}, {"parent", e.Parent}},
+	}
+}
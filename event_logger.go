@@ -0,0 +1,139 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// NewTextEventLogger returns an EventHandler that writes events to w, one
+// per line, as space-separated key=value pairs in the format of the
+// log/slog TextHandler. Timestamps are not included.
+//
+//	level=INFO msg=provided function=main.NewServer location=/src/main.go:12 scope="" outputs=[*main.Server]
+func NewTextEventLogger(w io.Writer) EventHandler {
+	return &eventLogger{w: w, encode: encodeTextEntry}
+}
+
+// NewJSONEventLogger returns an EventHandler that writes events to w as
+// JSON objects, one per line, in the format of the log/slog JSONHandler.
+// Timestamps are not included.
+//
+//	{"level":"INFO","msg":"provided","function":"main.NewServer","location":"/src/main.go:12","scope":"","outputs":["*main.Server"]}
+func NewJSONEventLogger(w io.Writer) EventHandler {
+	return &eventLogger{w: w, encode: encodeJSONEntry}
+}
+
+// eventLogger writes events to an io.Writer. It's safe for concurrent use.
+type eventLogger struct {
+	mu     sync.Mutex
+	w      io.Writer
+	encode func(*bytes.Buffer, eventEntry)
+}
+
+func (l *eventLogger) HandleEvent(e Event) {
+	var buf bytes.Buffer
+	l.encode(&buf, e.logEntry())
+	buf.WriteByte('\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.w.Write(buf.Bytes())
+}
+
+func (e eventEntry) level() string {
+	if e.Err != nil {
+		return "ERROR"
+	}
+	return "INFO"
+}
+
+func encodeTextEntry(buf *bytes.Buffer, e eventEntry) {
+	buf.WriteString("level=")
+	buf.WriteString(e.level())
+	buf.WriteString(" msg=")
+	buf.WriteString(textValue(e.Msg))
+	for _, a := range e.Attrs {
+		buf.WriteByte(' ')
+		buf.WriteString(a.Key)
+		buf.WriteByte('=')
+		switch v := a.Value.(type) {
+		case []string:
+			buf.WriteString(textValue("[" + strings.Join(v, " ") + "]"))
+		case time.Duration:
+			buf.WriteString(textValue(v.String()))
+		default:
+			buf.WriteString(textValue(v.(string)))
+		}
+	}
+	if e.Err != nil {
+		buf.WriteString(" error=")
+		buf.WriteString(textValue(e.Err.Error()))
+	}
+}
+
+// textValue quotes s if it can't be written as is in a key=value pair.
+func textValue(s string) string {
+	if s == "" {
+		return `""`
+	}
+	for _, r := range s {
+		if unicode.IsSpace(r) || r == '"' || r == '=' || !unicode.IsPrint(r) {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
+
+func encodeJSONEntry(buf *bytes.Buffer, e eventEntry) {
+	buf.WriteString(`{"level":`)
+	writeJSONValue(buf, e.level())
+	buf.WriteString(`,"msg":`)
+	writeJSONValue(buf, e.Msg)
+	for _, a := range e.Attrs {
+		buf.WriteByte(',')
+		writeJSONValue(buf, a.Key)
+		buf.WriteByte(':')
+		if d, ok := a.Value.(time.Duration); ok {
+			writeJSONValue(buf, d.String())
+		} else {
+			writeJSONValue(buf, a.Value)
+		}
+	}
+	if e.Err != nil {
+		buf.WriteString(`,"error":`)
+		writeJSONValue(buf, e.Err.Error())
+	}
+	buf.WriteByte('}')
+}
+
+func writeJSONValue(buf *bytes.Buffer, v interface{}) {
+	// Strings and string slices always marshal successfully.
+	b, _ := json.Marshal(v)
+	buf.Write(b)
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+//go:build go1.21
+
+package dig
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// NewSlogEventHandler returns an EventHandler that logs events with the
+// given slog.Logger. Events that carry an error are logged at the error
+// level, and the others at the info level.
+func NewSlogEventHandler(l *slog.Logger) EventHandler {
+	return slogEventHandler{l}
+}
+
+type slogEventHandler struct{ l *slog.Logger }
+
+func (h slogEventHandler) HandleEvent(e Event) {
+	entry := e.logEntry()
+
+	level := slog.LevelInfo
+	if entry.Err != nil {
+		level = slog.LevelError
+	}
+
+	attrs := make([]slog.Attr, 0, len(entry.Attrs)+1)
+	for _, a := range entry.Attrs {
+		switch v := a.Value.(type) {
+		case string:
+			attrs = append(attrs, slog.String(a.Key, v))
+		case time.Duration:
+			attrs = append(attrs, slog.Duration(a.Key, v))
+		default:
+			attrs = append(attrs, slog.Any(a.Key, v))
+		}
+	}
+	if entry.Err != nil {
+		attrs = append(attrs, slog.Any("error", entry.Err))
+	}
+
+	h.l.LogAttrs(context.Background(), level, entry.Msg, attrs...)
+}
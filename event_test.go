@@ -0,0 +1,305 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/dig"
+	"go.uber.org/dig/internal/digtest"
+)
+
+// eventRecorder is an EventHandler that records the events it receives.
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []dig.Event
+}
+
+func (r *eventRecorder) HandleEvent(e dig.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, e)
+}
+
+// Types returns the types of the recorded events, in order.
+func (r *eventRecorder) Types() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	types := make([]string, len(r.events))
+	for i, e := range r.events {
+		types[i] = fmt.Sprintf("%T", e)
+	}
+	return types
+}
+
+func (r *eventRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = nil
+}
+
+func outputStrings(outputs []*dig.Output) []string {
+	ss := make([]string, len(outputs))
+	for i, o := range outputs {
+		ss[i] = o.String()
+	}
+	return ss
+}
+
+func TestEventHandler(t *testing.T) {
+	t.Parallel()
+
+	type A struct{}
+	type B struct{}
+
+	t.Run("provide and invoke", func(t *testing.T) {
+		t.Parallel()
+
+		var rec eventRecorder
+		c := digtest.New(t, dig.WithEventHandler(&rec))
+		c.RequireProvide(func() A { return A{} })
+		c.RequireProvide(func(A) B { return B{} }, dig.Name("b"))
+
+		require.Len(t, rec.events, 2)
+		provided := rec.events[1].(dig.Provided)
+		assert.Equal(t, "TestEventHandler.func1.2", provided.Location.Name)
+		assert.Equal(t, "go.uber.org/dig_test", provided.Location.Package)
+		assert.Equal(t, []string{`dig_test.B[name = "b"]`}, outputStrings(provided.Outputs))
+		rec.Reset()
+
+		type in struct {
+			dig.In
+
+			B B `name:"b"`
+		}
+		c.RequireInvoke(func(in) {})
+		assert.Equal(t, []string{
+			"dig.Invoking",
+			"dig.ConstructorStart",
+			"dig.ConstructorEnd",
+			"dig.ConstructorStart",
+			"dig.ConstructorEnd",
+			"dig.Invoked",
+		}, rec.Types())
+
+		start := rec.events[1].(dig.ConstructorStart)
+		assert.Equal(t, "TestEventHandler.func1.1", start.Location.Name)
+		assert.Equal(t, []string{"dig_test.A"}, outputStrings(start.Outputs))
+
+		invoked := rec.events[5].(dig.Invoked)
+		require.Len(t, invoked.Inputs, 1)
+		assert.Equal(t, `dig_test.B[name = "b"]`, invoked.Inputs[0].String())
+		assert.NoError(t, invoked.Err)
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		t.Parallel()
+
+		var rec eventRecorder
+		c := digtest.New(t, dig.WithEventHandler(&rec))
+
+		err := c.Provide(func() {})
+		require.Error(t, err)
+		require.Len(t, rec.events, 1)
+		failed := rec.events[0].(dig.ProvideFailed)
+		assert.Equal(t, err, failed.Err)
+		assert.Equal(t, "TestEventHandler.func2.1", failed.Location.Name)
+		rec.Reset()
+
+		c.RequireProvide(func() (A, error) { return A{}, errors.New("great sadness") })
+		err = c.Invoke(func(A) {})
+		require.Error(t, err)
+		assert.Equal(t, []string{
+			"dig.Provided",
+			"dig.Invoking",
+			"dig.ConstructorStart",
+			"dig.ConstructorEnd",
+			"dig.Invoked",
+		}, rec.Types())
+
+		end := rec.events[3].(dig.ConstructorEnd)
+		assert.ErrorContains(t, end.Err, "great sadness")
+		invoked := rec.events[4].(dig.Invoked)
+		assert.Equal(t, err, invoked.Err)
+	})
+
+	t.Run("decorators and scopes", func(t *testing.T) {
+		t.Parallel()
+
+		var rec eventRecorder
+		c := digtest.New(t, dig.WithEventHandler(&rec))
+		c.RequireProvide(func() A { return A{} })
+
+		child := c.Scope("child")
+		child.RequireDecorate(func(A) A { return A{} })
+		err := child.Decorate(func(A) A { return A{} })
+		require.Error(t, err)
+		child.RequireInvoke(func(A) {})
+
+		assert.Equal(t, []string{
+			"dig.Provided",
+			"dig.ScopeCreated",
+			"dig.Decorated",
+			"dig.Decorated",
+			"dig.Invoking",
+			"dig.ConstructorStart",
+			"dig.ConstructorEnd",
+			"dig.DecoratorStart",
+			"dig.DecoratorEnd",
+			"dig.Invoked",
+		}, rec.Types())
+
+		assert.Equal(t, dig.ScopeCreated{Name: "child", Parent: ""}, rec.events[1])
+
+		decorated := rec.events[2].(dig.Decorated)
+		assert.Equal(t, "child", decorated.Scope)
+		assert.Equal(t, []string{"dig_test.A"}, outputStrings(decorated.Outputs))
+		assert.NoError(t, decorated.Err)
+		assert.Equal(t, err, rec.events[3].(dig.Decorated).Err)
+
+		assert.Equal(t, "", rec.events[5].(dig.ConstructorStart).Scope)
+		assert.Equal(t, "child", rec.events[7].(dig.DecoratorStart).Scope)
+		assert.Equal(t, "child", rec.events[9].(dig.Invoked).Scope)
+	})
+
+	t.Run("multiple handlers", func(t *testing.T) {
+		t.Parallel()
+
+		var rec1, rec2 eventRecorder
+		c := digtest.New(t, dig.WithEventHandler(&rec1), dig.WithEventHandler(&rec2))
+		c.RequireProvide(func() A { return A{} })
+
+		assert.Equal(t, []string{"dig.Provided"}, rec1.Types())
+		assert.Equal(t, []string{"dig.Provided"}, rec2.Types())
+	})
+
+	t.Run("handler func", func(t *testing.T) {
+		t.Parallel()
+
+		var names []string
+		c := digtest.New(t, dig.WithEventHandler(dig.EventHandlerFunc(func(e dig.Event) {
+			if sc, ok := e.(dig.ScopeCreated); ok {
+				names = append(names, sc.Name)
+			}
+		})))
+		c.Scope("foo").Scope("bar")
+
+		assert.Equal(t, []string{"foo", "bar"}, names)
+	})
+}
+
+func TestEventLoggers(t *testing.T) {
+	t.Parallel()
+
+	loc := dig.Location{Name: "NewFoo", Package: "example.com/foo", File: "/src/foo.go", Line: 42}
+	events := []dig.Event{
+		dig.ScopeCreated{Name: "request handler", Parent: ""},
+		dig.Invoked{
+			Location: loc,
+			Scope:    "child",
+			Runtime:  1500 * time.Microsecond,
+			Err:      errors.New(`great "sadness"`),
+		},
+	}
+
+	t.Run("text", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		logger := dig.NewTextEventLogger(&buf)
+		for _, e := range events {
+			logger.HandleEvent(e)
+		}
+
+		assert.Equal(t, []string{
+			`level=INFO msg="scope created" scope="request handler" parent=""`,
+			`level=ERROR msg=invoked function=example.com/foo.NewFoo location=/src/foo.go:42 scope=child inputs=[] runtime=1.5ms error="great \"sadness\""`,
+		}, strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n"))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		logger := dig.NewJSONEventLogger(&buf)
+		for _, e := range events {
+			logger.HandleEvent(e)
+		}
+
+		assert.Equal(t, []string{
+			`{"level":"INFO","msg":"scope created","scope":"request handler","parent":""}`,
+			`{"level":"ERROR","msg":"invoked","function":"example.com/foo.NewFoo","location":"/src/foo.go:42","scope":"child","inputs":[],"runtime":"1.5ms","error":"great \"sadness\""}`,
+		}, strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n"))
+	})
+
+	t.Run("container", func(t *testing.T) {
+		t.Parallel()
+
+		type A struct{}
+
+		var buf bytes.Buffer
+		c := digtest.New(t, dig.WithEventHandler(dig.NewTextEventLogger(&buf)))
+		c.RequireProvide(func() A { return A{} })
+
+		assert.Contains(t, buf.String(), `level=INFO msg=provided function=go.uber.org/dig_test.TestEventLoggers.func3.1`)
+		assert.Contains(t, buf.String(), `scope="" outputs=[dig_test.A]`)
+	})
+}
+
+func TestWithEventHandlerString(t *testing.T) {
+	var rec eventRecorder
+	assert.Equal(t, "WithEventHandler(*dig_test.eventRecorder)", fmt.Sprint(dig.WithEventHandler(&rec)))
+}
+
+func TestEventHandlerParallel(t *testing.T) {
+	t.Parallel()
+
+	type A struct{}
+	type B struct{}
+	type C struct{}
+
+	var rec eventRecorder
+	c := digtest.New(t, dig.WithEventHandler(&rec), dig.ParallelConstruction(4))
+	c.RequireProvide(func() A { return A{} })
+	c.RequireProvide(func() B { return B{} })
+	c.RequireProvide(func(A, B) C { return C{} })
+	c.RequireInvoke(func(C) {})
+
+	var starts, ends int
+	for _, typ := range rec.Types() {
+		switch typ {
+		case "dig.ConstructorStart":
+			starts++
+		case "dig.ConstructorEnd":
+			ends++
+		}
+	}
+	assert.Equal(t, 3, starts)
+	assert.Equal(t, 3, ends)
+}
@@ -0,0 +1,354 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"go.uber.org/dig/internal/dot"
+)
+
+// GraphFormat is a format that ExportGraph can write the dependency graph
+// of a Container in.
+type GraphFormat int
+
+const (
+	// GraphFormatDOT is the Graphviz DOT format written by Visualize.
+	GraphFormatDOT GraphFormat = iota
+
+	// GraphFormatJSON is a JSON document meant to be consumed by other
+	// programs. See ExportGraph for its schema.
+	GraphFormatJSON
+
+	// GraphFormatMermaid is a Mermaid flowchart, which can be embedded in
+	// Markdown documents.
+	GraphFormatMermaid
+)
+
+// String returns the name of the format.
+func (f GraphFormat) String() string {
+	switch f {
+	case GraphFormatDOT:
+		return "DOT"
+	case GraphFormatJSON:
+		return "JSON"
+	case GraphFormatMermaid:
+		return "Mermaid"
+	default:
+		return fmt.Sprintf("GraphFormat(%d)", int(f))
+	}
+}
+
+// GraphSchemaVersion is the version of the schema of the JSON documents
+// written by ExportGraph. It is increased whenever fields are changed or
+// removed in a way that may break existing consumers. Adding fields does
+// not change the version.
+const GraphSchemaVersion = 1
+
+// ExportGraph writes the dependency graph of the Container to w in the
+// given format. It accepts the same options as Visualize, and is
+// equivalent to calling Visualize with the VisualizeFormat option.
+//
+// With GraphFormatJSON, the output is a JSON document with the following
+// structure, where optional fields are omitted when they are unset.
+//
+//	{
+//	  "version": 1,
+//	  "scopes": [
+//	    {"id": 0, "name": "", "parent": -1},
+//	    {"id": 1, "name": "child", "parent": 0}
+//	  ],
+//	  "constructors": [
+//	    {
+//	      "id": 824634330112,
+//	      "name": "NewServer",
+//	      "package": "example.com/server",
+//	      "file": "/src/server/server.go",
+//	      "line": 42,
+//	      "scope": 0,
+//	      "transient": false,
+//	      "perScope": false,
+//	      "exported": false,
+//	      "params": [
+//	        {"type": "*log.Logger", "name": "app", "optional": true, "lazy": true},
+//	        {"type": "[]http.Handler", "group": "routes", "soft": true}
+//	      ],
+//	      "results": [
+//	        {"type": "*server.Server"},
+//	        {"type": "http.Handler", "group": "routes", "groupIndex": 0, "flatten": true}
+//	      ],
+//	      "error": "rootCause"
+//	    }
+//	  ],
+//	  "decorators": [
+//	    {"id": 824634330113, "name": "...", "package": "...", "file": "...", "line": 7,
+//	     "scope": 1, "params": [...], "results": [...]}
+//	  ],
+//	  "groups": [
+//	    {
+//	      "type": "http.Handler",
+//	      "name": "routes",
+//	      "results": [{"constructor": 824634330112, "groupIndex": 0}],
+//	      "error": "transitiveFailure"
+//	    }
+//	  ],
+//	  "failures": {
+//	    "rootCauses": [{"type": "*log.Logger"}],
+//	    "transitiveFailures": [{"type": "*server.Server"}]
+//	  }
+//	}
+//
+// Failures are only reported when VisualizeError is used. As with
+// Visualize, the nodes that did not fail are then left out of the graph.
+func ExportGraph(c *Container, w io.Writer, format GraphFormat, opts ...VisualizeOption) error {
+	return Visualize(c, w, append(opts, VisualizeFormat(format))...)
+}
+
+// graphWriter returns the function that writes graphs in the format
+// specified by the options.
+func graphWriter(opts *visualizeOptions) (func(io.Writer, *dot.Graph) error, error) {
+	switch format := opts.Format; format {
+	case GraphFormatDOT:
+		return func(w io.Writer, dg *dot.Graph) error {
+			writeDOT(w, dg, opts.Runtime)
+			return nil
+		}, nil
+	case GraphFormatJSON:
+		return writeJSONGraph, nil
+	case GraphFormatMermaid:
+		return func(w io.Writer, dg *dot.Graph) error {
+			visualizeMermaid(w, dg)
+			return nil
+		}, nil
+	default:
+		return nil, newErrInvalidInput(fmt.Sprintf("unknown graph format %v", format), nil)
+	}
+}
+
+type jsonGraph struct {
+	Version      int               `json:"version"`
+	Scopes       []jsonScope       `json:"scopes"`
+	Constructors []jsonConstructor `json:"constructors"`
+	Decorators   []jsonDecorator   `json:"decorators"`
+	Groups       []jsonGroup       `json:"groups"`
+	Failures     *jsonFailures     `json:"failures,omitempty"`
+}
+
+type jsonScope struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Parent int    `json:"parent"`
+}
+
+type jsonConstructor struct {
+	ID        uint64       `json:"id"`
+	Name      string       `json:"name"`
+	Package   string       `json:"package"`
+	File      string       `json:"file"`
+	Line      int          `json:"line"`
+	Scope     int          `json:"scope"`
+	Transient bool         `json:"transient"`
+	PerScope  bool         `json:"perScope"`
+	Exported  bool         `json:"exported"`
+	Params    []jsonParam  `json:"params"`
+	Results   []jsonResult `json:"results"`
+	Error     string       `json:"error,omitempty"`
+}
+
+type jsonDecorator struct {
+	ID      uint64       `json:"id"`
+	Name    string       `json:"name"`
+	Package string       `json:"package"`
+	File    string       `json:"file"`
+	Line    int          `json:"line"`
+	Scope   int          `json:"scope"`
+	Params  []jsonParam  `json:"params"`
+	Results []jsonResult `json:"results"`
+}
+
+type jsonParam struct {
+	Type     string `json:"type"`
+	Name     string `json:"name,omitempty"`
+	Group    string `json:"group,omitempty"`
+	Optional bool   `json:"optional,omitempty"`
+	Lazy     bool   `json:"lazy,omitempty"`
+	Soft     bool   `json:"soft,omitempty"`
+}
+
+type jsonResult struct {
+	Type       string `json:"type"`
+	Name       string `json:"name,omitempty"`
+	Group      string `json:"group,omitempty"`
+	GroupIndex *int   `json:"groupIndex,omitempty"`
+	Flatten    bool   `json:"flatten,omitempty"`
+}
+
+type jsonGroup struct {
+	Type    string            `json:"type"`
+	Name    string            `json:"name"`
+	Results []jsonGroupResult `json:"results"`
+	Error   string            `json:"error,omitempty"`
+}
+
+type jsonGroupResult struct {
+	Constructor uint64 `json:"constructor"`
+	GroupIndex  int    `json:"groupIndex"`
+}
+
+type jsonFailures struct {
+	RootCauses         []jsonResult `json:"rootCauses"`
+	TransitiveFailures []jsonResult `json:"transitiveFailures"`
+}
+
+func writeJSONGraph(w io.Writer, dg *dot.Graph) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(newJSONGraph(dg))
+}
+
+func newJSONGraph(dg *dot.Graph) jsonGraph {
+	g := jsonGraph{
+		Version:      GraphSchemaVersion,
+		Scopes:       make([]jsonScope, 0, len(dg.Scopes)),
+		Constructors: make([]jsonConstructor, 0, len(dg.Ctors)),
+		Decorators:   make([]jsonDecorator, 0, len(dg.Decorators)),
+		Groups:       make([]jsonGroup, 0, len(dg.Groups)),
+	}
+
+	for _, s := range dg.Scopes {
+		g.Scopes = append(g.Scopes, jsonScope{ID: s.ID, Name: s.Name, Parent: s.Parent})
+	}
+
+	// Group results don't know which constructor produced them.
+	producers := make(map[*dot.Result]dot.CtorID)
+	for _, c := range dg.Ctors {
+		params := newJSONParams(c.Params)
+		for _, gp := range c.GroupParams {
+			params = append(params, jsonParam{
+				Type:  "[]" + gp.Type.String(),
+				Group: gp.Name,
+				Soft:  c.IsSoftGroupParam(gp),
+			})
+		}
+
+		g.Constructors = append(g.Constructors, jsonConstructor{
+			ID:        uint64(c.ID),
+			Name:      c.Name,
+			Package:   c.Package,
+			File:      c.File,
+			Line:      c.Line,
+			Scope:     c.Scope,
+			Transient: c.Transient,
+			PerScope:  c.PerScope,
+			Exported:  c.Exported,
+			Params:    params,
+			Results:   newJSONResults(c.Results),
+			Error:     jsonErrorType(c.ErrorType),
+		})
+		for _, r := range c.Results {
+			producers[r] = c.ID
+		}
+	}
+
+	for _, d := range dg.Decorators {
+		g.Decorators = append(g.Decorators, jsonDecorator{
+			ID:      uint64(d.ID),
+			Name:    d.Name,
+			Package: d.Package,
+			File:    d.File,
+			Line:    d.Line,
+			Scope:   d.Scope,
+			Params:  newJSONParams(d.Params),
+			Results: newJSONResults(d.Results),
+		})
+	}
+
+	for _, gr := range dg.Groups {
+		results := make([]jsonGroupResult, 0, len(gr.Results))
+		for _, r := range gr.Results {
+			results = append(results, jsonGroupResult{
+				Constructor: uint64(producers[r]),
+				GroupIndex:  r.GroupIndex,
+			})
+		}
+		g.Groups = append(g.Groups, jsonGroup{
+			Type:    gr.Type.String(),
+			Name:    gr.Name,
+			Results: results,
+			Error:   jsonErrorType(gr.ErrorType),
+		})
+	}
+
+	if f := dg.Failed; len(f.RootCauses) > 0 || len(f.TransitiveFailures) > 0 {
+		g.Failures = &jsonFailures{
+			RootCauses:         newJSONResults(f.RootCauses),
+			TransitiveFailures: newJSONResults(f.TransitiveFailures),
+		}
+	}
+
+	return g
+}
+
+func newJSONParams(params []*dot.Param) []jsonParam {
+	out := make([]jsonParam, 0, len(params))
+	for _, p := range params {
+		out = append(out, jsonParam{
+			Type:     p.Type.String(),
+			Name:     p.Name,
+			Group:    p.Group,
+			Optional: p.Optional,
+			Lazy:     p.Lazy,
+			Soft:     p.Soft,
+		})
+	}
+	return out
+}
+
+func newJSONResults(results []*dot.Result) []jsonResult {
+	out := make([]jsonResult, 0, len(results))
+	for _, r := range results {
+		jr := jsonResult{
+			Type:    r.Type.String(),
+			Name:    r.Name,
+			Group:   r.Group,
+			Flatten: r.Flatten,
+		}
+		if r.Group != "" {
+			idx := r.GroupIndex
+			jr.GroupIndex = &idx
+		}
+		out = append(out, jr)
+	}
+	return out
+}
+
+func jsonErrorType(t dot.ErrorType) string {
+	switch {
+	case t.IsRootCause():
+		return "rootCause"
+	case t.IsTransitiveFailure():
+		return "transitiveFailure"
+	default:
+		return ""
+	}
+}
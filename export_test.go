@@ -0,0 +1,227 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/dig"
+	"go.uber.org/dig/internal/digtest"
+)
+
+func TestGraphFormatString(t *testing.T) {
+	assert.Equal(t, "DOT", dig.GraphFormatDOT.String())
+	assert.Equal(t, "JSON", dig.GraphFormatJSON.String())
+	assert.Equal(t, "GraphFormat(42)", fmt.Sprint(dig.GraphFormat(42)))
+}
+
+func TestExportGraph(t *testing.T) {
+	t.Parallel()
+
+	type A struct{}
+	type B struct{}
+	type C struct{}
+
+	type graph struct {
+		Version int `json:"version"`
+		Scopes  []struct {
+			ID     int    `json:"id"`
+			Name   string `json:"name"`
+			Parent int    `json:"parent"`
+		} `json:"scopes"`
+		Constructors []struct {
+			ID        uint64                   `json:"id"`
+			Name      string                   `json:"name"`
+			Package   string                   `json:"package"`
+			File      string                   `json:"file"`
+			Line      int                      `json:"line"`
+			Scope     int                      `json:"scope"`
+			Transient bool                     `json:"transient"`
+			Exported  bool                     `json:"exported"`
+			Params    []map[string]interface{} `json:"params"`
+			Results   []map[string]interface{} `json:"results"`
+			Error     string                   `json:"error"`
+		} `json:"constructors"`
+		Decorators []struct {
+			Scope   int                      `json:"scope"`
+			Params  []map[string]interface{} `json:"params"`
+			Results []map[string]interface{} `json:"results"`
+		} `json:"decorators"`
+		Groups []struct {
+			Type    string `json:"type"`
+			Name    string `json:"name"`
+			Results []struct {
+				Constructor uint64 `json:"constructor"`
+				GroupIndex  int    `json:"groupIndex"`
+			} `json:"results"`
+			Error string `json:"error"`
+		} `json:"groups"`
+		Failures *struct {
+			RootCauses         []map[string]interface{} `json:"rootCauses"`
+			TransitiveFailures []map[string]interface{} `json:"transitiveFailures"`
+		} `json:"failures"`
+	}
+
+	export := func(t *testing.T, c *dig.Container, opts ...dig.VisualizeOption) graph {
+		var b bytes.Buffer
+		require.NoError(t, dig.ExportGraph(c, &b, dig.GraphFormatJSON, opts...))
+
+		var g graph
+		require.NoError(t, json.Unmarshal(b.Bytes(), &g), "invalid JSON:\n%s", b.String())
+		return g
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		var b bytes.Buffer
+		require.NoError(t, dig.ExportGraph(digtest.New(t).Container, &b, dig.GraphFormatJSON))
+		assert.JSONEq(t, `{
+			"version": 1,
+			"scopes": [{"id": 0, "name": "", "parent": -1}],
+			"constructors": [],
+			"decorators": [],
+			"groups": []
+		}`, b.String())
+	})
+
+	t.Run("DOT", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() *A { return nil })
+
+		var want, got bytes.Buffer
+		require.NoError(t, dig.Visualize(c.Container, &want))
+		require.NoError(t, dig.ExportGraph(c.Container, &got, dig.GraphFormatDOT))
+		assert.Equal(t, want.String(), got.String())
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		t.Parallel()
+
+		err := dig.ExportGraph(digtest.New(t).Container, new(bytes.Buffer), dig.GraphFormat(42))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown graph format GraphFormat(42)")
+	})
+
+	t.Run("constructors", func(t *testing.T) {
+		t.Parallel()
+
+		type params struct {
+			dig.In
+
+			A  *A   `name:"a" optional:"true"`
+			Bs []*B `group:"bs,soft"`
+			C  dig.Lazy[*C]
+		}
+		type results struct {
+			dig.Out
+
+			Bs []*B `group:"bs,flatten"`
+		}
+
+		c := digtest.New(t)
+		c.RequireProvide(func() *A { return nil }, dig.Name("a"))
+		c.RequireProvide(func() results { return results{} })
+		c.RequireProvide(func(params) *C { return nil }, dig.Transient())
+		c.Scope("child").RequireProvide(func(*C) string { return "" }, dig.Export(true))
+
+		g := export(t, c.Container)
+		assert.Equal(t, dig.GraphSchemaVersion, g.Version)
+		require.Len(t, g.Scopes, 2)
+		assert.Equal(t, "child", g.Scopes[1].Name)
+		assert.Equal(t, 0, g.Scopes[1].Parent)
+
+		require.Len(t, g.Constructors, 4)
+		a := g.Constructors[0]
+		assert.Equal(t, "go.uber.org/dig_test", a.Package)
+		assert.Contains(t, a.File, "export_test.go")
+		assert.NotZero(t, a.Line)
+		assert.NotZero(t, a.ID)
+		assert.Equal(t, []map[string]interface{}{{"type": "*dig_test.A", "name": "a"}}, a.Results)
+
+		assert.Equal(t, []map[string]interface{}{
+			{"type": "*dig_test.B", "group": "bs", "groupIndex": float64(0), "flatten": true},
+		}, g.Constructors[1].Results)
+
+		ctor := g.Constructors[2]
+		assert.True(t, ctor.Transient)
+		assert.Equal(t, []map[string]interface{}{
+			{"type": "*dig_test.A", "name": "a", "optional": true},
+			{"type": "*dig_test.C", "lazy": true},
+			{"type": "[]*dig_test.B", "group": "bs", "soft": true},
+		}, ctor.Params)
+
+		exported := g.Constructors[3]
+		assert.True(t, exported.Exported)
+		assert.Equal(t, 1, exported.Scope)
+
+		require.Len(t, g.Groups, 1)
+		assert.Equal(t, "*dig_test.B", g.Groups[0].Type)
+		assert.Equal(t, "bs", g.Groups[0].Name)
+		require.Len(t, g.Groups[0].Results, 1)
+		assert.Equal(t, g.Constructors[1].ID, g.Groups[0].Results[0].Constructor)
+
+		assert.Nil(t, g.Failures)
+	})
+
+	t.Run("decorators", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() *A { return nil })
+		c.Scope("child").RequireDecorate(func(a *A) *A { return a })
+
+		g := export(t, c.Container)
+		require.Len(t, g.Decorators, 1)
+		assert.Equal(t, 1, g.Decorators[0].Scope)
+		assert.Equal(t, []map[string]interface{}{{"type": "*dig_test.A"}}, g.Decorators[0].Params)
+		assert.Equal(t, []map[string]interface{}{{"type": "*dig_test.A"}}, g.Decorators[0].Results)
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() (*A, error) { return nil, errors.New("great sadness") })
+		c.RequireProvide(func(*A) *B { return nil })
+		c.RequireProvide(func() *C { return nil })
+
+		err := c.Invoke(func(*B) {})
+		require.Error(t, err)
+
+		g := export(t, c.Container, dig.VisualizeError(err))
+		require.Len(t, g.Constructors, 2)
+		assert.Equal(t, "rootCause", g.Constructors[0].Error)
+		assert.Equal(t, "transitiveFailure", g.Constructors[1].Error)
+
+		require.NotNil(t, g.Failures)
+		assert.Equal(t, []map[string]interface{}{{"type": "*dig_test.A"}}, g.Failures.RootCauses)
+		assert.Equal(t, []map[string]interface{}{{"type": "*dig_test.B"}}, g.Failures.TransitiveFailures)
+	})
+}
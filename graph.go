@@ -69,7 +69,7 @@ func (gh *graphHolder) EdgesFrom(u int) []int {
 			orders = append(orders, getParamOrder(gh, param)...)
 		}
 	case *paramGroupedSlice:
-		providers := gh.s.getAllGroupProviders(w.Group, w.Type.Elem())
+		providers := gh.s.getAllProviders(key{group: w.Group, t: w.Type.Elem()})
 		for _, provider := range providers {
 			orders = append(orders, provider.Order(gh.s))
 		}
@@ -22,18 +22,20 @@ package dig
 
 import (
 	"container/list"
+	"context"
 	"fmt"
 	"reflect"
 	"strconv"
 )
 
 var (
-	_noValue    reflect.Value
-	_errType    = reflect.TypeOf((*error)(nil)).Elem()
-	_inPtrType  = reflect.TypeOf((*In)(nil))
-	_inType     = reflect.TypeOf(In{})
-	_outPtrType = reflect.TypeOf((*Out)(nil))
-	_outType    = reflect.TypeOf(Out{})
+	_noValue     reflect.Value
+	_errType     = reflect.TypeOf((*error)(nil)).Elem()
+	_contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	_inPtrType   = reflect.TypeOf((*In)(nil))
+	_inType      = reflect.TypeOf(In{})
+	_outPtrType  = reflect.TypeOf((*Out)(nil))
+	_outType     = reflect.TypeOf(Out{})
 )
 
 // Placeholder type placed in dig.In/dig.out to make their special nature
@@ -28,6 +28,7 @@ import (
 type Clock interface {
 	Now() time.Time
 	Since(time.Time) time.Duration
+	After(time.Duration) <-chan time.Time
 }
 
 // System is the default implementation of Clock based on real time.
@@ -43,6 +44,10 @@ func (systemClock) Since(t time.Time) time.Duration {
 	return time.Since(t)
 }
 
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
 // Mock is a fake source of time.
 // It implements standard time operations, but allows
 // the user to control the passage of time.
@@ -71,6 +76,15 @@ func (m *Mock) Since(t time.Time) time.Duration {
 	return m.Now().Sub(t)
 }
 
+// After progresses time by the given duration and returns a channel that
+// holds the new current time. Nothing ever waits on a mock clock.
+func (m *Mock) After(d time.Duration) <-chan time.Time {
+	m.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- m.now
+	return ch
+}
+
 // Add progresses time by the given duration.
 //
 // It panics if the duration is negative.
@@ -45,6 +45,12 @@ func testClock(t *testing.T, clock Clock, advance func(d time.Duration)) {
 		advance(1 * time.Millisecond)
 		assert.NotZero(t, clock.Since(now), "time must have advanced")
 	})
+
+	t.Run("After", func(t *testing.T) {
+		start := clock.Now()
+		fired := <-clock.After(time.Millisecond)
+		assert.False(t, fired.Before(start.Add(time.Millisecond)), "time must have advanced")
+	})
 }
 
 func TestMock_AddNegative(t *testing.T) {
@@ -23,6 +23,7 @@ package dot
 import (
 	"fmt"
 	"reflect"
+	"time"
 )
 
 // ErrorType of a constructor or group is updated when they fail to build.
@@ -48,6 +49,38 @@ type Ctor struct {
 	GroupParams []*Group
 	Results     []*Result
 	ErrorType   ErrorType
+
+	// Transient is true if the constructor is called for every dependent.
+	Transient bool
+
+	// PerScope is true if the constructor is called for every Scope that
+	// requests its values.
+	PerScope bool
+
+	// Exported is true if the constructor was provided to all Scopes.
+	Exported bool
+
+	// Scope is the ID of the Scope the constructor was provided to.
+	Scope int
+
+	// Called is true if the constructor was called, or is being called.
+	Called bool
+
+	// Calls is the number of calls to the constructor that returned, and
+	// Runtime the total time spent in them.
+	Calls   int
+	Runtime time.Duration
+
+	// softGroups holds the value groups that the constructor consumes
+	// softly.
+	softGroups map[nodeKey]struct{}
+}
+
+// IsSoftGroupParam reports whether the constructor consumes the given
+// value group softly.
+func (c *Ctor) IsSoftGroupParam(g *Group) bool {
+	_, ok := c.softGroups[g.nodeKey()]
+	return ok
 }
 
 // removeParam deletes the dependency on the provided result's nodeKey.
@@ -84,6 +117,13 @@ type Param struct {
 	*Node
 
 	Optional bool
+
+	// Lazy is true if the value is only built when the constructor asks
+	// for it.
+	Lazy bool
+
+	// Soft is true for value groups that are consumed softly.
+	Soft bool
 }
 
 // Result is a result node in the graph. Results are the output of constructors.
@@ -95,6 +135,10 @@ type Result struct {
 	// representations are the same so we need indices to uniquely identify
 	// the values.
 	GroupIndex int
+
+	// Flatten is true if the elements of the result are added to its
+	// group individually.
+	Flatten bool
 }
 
 // Group is a group node in the graph. Group represents an fx value group.
@@ -121,11 +165,37 @@ func (g *Group) removeResult(r *Result) {
 	g.Results = pruned
 }
 
+// Decorator encodes a decorator registered with the container.
+type Decorator struct {
+	Name    string
+	Package string
+	File    string
+	Line    int
+	ID      CtorID
+	Params  []*Param
+	Results []*Result
+
+	// Scope is the ID of the Scope the decorator was registered with.
+	Scope int
+}
+
+// Scope encodes a Scope of the container. The root Scope has ID 0 and
+// no parent.
+type Scope struct {
+	ID     int
+	Name   string
+	Parent int // -1 for the root Scope
+}
+
 // Graph is the DOT-format graph in a Container.
 type Graph struct {
 	Ctors   []*Ctor
 	ctorMap map[CtorID]*Ctor
 
+	Decorators []*Decorator
+
+	Scopes []*Scope
+
 	Groups   []*Group
 	groupMap map[nodeKey]*Group
 
@@ -152,6 +222,10 @@ type FailedNodes struct {
 	// Groups is a collection of failed groupKeys that is populated as the graph is traversed
 	// for errors.
 	groups map[nodeKey]struct{}
+
+	// Whether a root cause was added since the current chain of failures
+	// started. See StartFailureChain.
+	chainHasRootCause bool
 }
 
 // NewGraph creates an empty graph.
@@ -195,6 +269,12 @@ func (dg *Graph) AddCtor(c *Ctor, paramList []*Param, resultList []*Result) {
 		k := nodeKey{t: param.Type.Elem(), group: param.Group}
 		group := dg.getGroup(k)
 		groupParams = append(groupParams, group)
+		if param.Soft {
+			if c.softGroups == nil {
+				c.softGroups = make(map[nodeKey]struct{})
+			}
+			c.softGroups[k] = struct{}{}
+		}
 	}
 
 	for _, result := range resultList {
@@ -219,6 +299,22 @@ func (dg *Graph) AddCtor(c *Ctor, paramList []*Param, resultList []*Result) {
 	dg.ctorMap[c.ID] = c
 }
 
+// AddDecorator adds the decorator with paramList and resultList into the
+// graph.
+func (dg *Graph) AddDecorator(d *Decorator, paramList []*Param, resultList []*Result) {
+	d.Params = paramList
+	d.Results = resultList
+	dg.Decorators = append(dg.Decorators, d)
+}
+
+// AddScope adds a Scope with the given name and parent into the graph and
+// returns its ID. The parent of the root Scope is -1.
+func (dg *Graph) AddScope(name string, parent int) int {
+	s := &Scope{ID: len(dg.Scopes), Name: name, Parent: parent}
+	dg.Scopes = append(dg.Scopes, s)
+	return s.ID
+}
+
 func (dg *Graph) failNode(r *Result, isRootCause bool) {
 	if isRootCause {
 		dg.addRootCause(r)
@@ -230,18 +326,25 @@ func (dg *Graph) failNode(r *Result, isRootCause bool) {
 // AddMissingNodes adds missing nodes to the list of failed Results in the graph.
 func (dg *Graph) AddMissingNodes(results []*Result) {
 	// The failure(s) are root causes if there are no other failures.
-	isRootCause := len(dg.Failed.RootCauses) == 0
+	isRootCause := !dg.Failed.chainHasRootCause
 
 	for _, r := range results {
 		dg.failNode(r, isRootCause)
 	}
 }
 
+// StartFailureChain starts a new chain of failures, from a root cause up
+// to the nodes that failed because of it. The first failure added after
+// this is a root cause, even if other chains were added before.
+func (dg *Graph) StartFailureChain() {
+	dg.Failed.chainHasRootCause = false
+}
+
 // FailNodes adds results to the list of failed Results in the graph, and
 // updates the state of the constructor with the given id accordingly.
 func (dg *Graph) FailNodes(results []*Result, id CtorID) {
 	// This failure is the root cause if there are no other failures.
-	isRootCause := len(dg.Failed.RootCauses) == 0
+	isRootCause := !dg.Failed.chainHasRootCause
 	dg.Failed.ctors[id] = struct{}{}
 
 	for _, r := range results {
@@ -262,7 +365,7 @@ func (dg *Graph) FailNodes(results []*Result, id CtorID) {
 // with the given id accordingly.
 func (dg *Graph) FailGroupNodes(name string, t reflect.Type, id CtorID) {
 	// This failure is the root cause if there are no other failures.
-	isRootCause := len(dg.Failed.RootCauses) == 0
+	isRootCause := !dg.Failed.chainHasRootCause
 
 	k := nodeKey{t: t, group: name}
 	group := dg.getGroup(k)
@@ -399,6 +502,186 @@ func (dg *Graph) pruneGroupResults(c *Ctor, groups map[nodeKey]*Group) {
 	}
 }
 
+// Providers returns the constructors that produce a value of type t,
+// regardless of its name or group.
+func (dg *Graph) Providers(t reflect.Type) []*Ctor {
+	var ctors []*Ctor
+	for _, c := range dg.Ctors {
+		for _, r := range c.Results {
+			if r.Type == t {
+				ctors = append(ctors, c)
+				break
+			}
+		}
+	}
+	return ctors
+}
+
+// Consumers returns the constructors that consume a value of type t,
+// either directly or as part of a value group, regardless of its name or
+// group.
+func (dg *Graph) Consumers(t reflect.Type) []*Ctor {
+	var ctors []*Ctor
+	for _, c := range dg.Ctors {
+		if c.consumes(t) {
+			ctors = append(ctors, c)
+		}
+	}
+	return ctors
+}
+
+func (c *Ctor) consumes(t reflect.Type) bool {
+	for _, p := range c.Params {
+		if p.Type == t {
+			return true
+		}
+	}
+	for _, g := range c.GroupParams {
+		if g.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Walk calls visit for the given constructors and for every constructor
+// reachable from them, at most once each. It follows the dependencies of
+// the constructors, or their dependents if reverse is true. Constructors
+// that are more than maxDepth edges away from the given constructors are
+// not visited. A negative maxDepth means no limit.
+func (dg *Graph) Walk(from []*Ctor, reverse bool, maxDepth int, visit func(*Ctor)) {
+	producers := make(map[nodeKey][]*Ctor)
+	consumers := make(map[nodeKey][]*Ctor)
+	for _, c := range dg.Ctors {
+		for _, r := range c.Results {
+			k := r.nodeKey()
+			producers[k] = append(producers[k], c)
+		}
+		for _, p := range c.Params {
+			k := p.nodeKey()
+			consumers[k] = append(consumers[k], c)
+		}
+		for _, g := range c.GroupParams {
+			k := g.nodeKey()
+			consumers[k] = append(consumers[k], c)
+		}
+	}
+
+	next := func(c *Ctor) []*Ctor {
+		var out []*Ctor
+		if reverse {
+			for _, r := range c.Results {
+				out = append(out, consumers[r.nodeKey()]...)
+			}
+			return out
+		}
+		for _, p := range c.Params {
+			out = append(out, producers[p.nodeKey()]...)
+		}
+		for _, g := range c.GroupParams {
+			out = append(out, producers[g.nodeKey()]...)
+		}
+		return out
+	}
+
+	// Breadth-first so that each constructor is reached at its smallest
+	// depth.
+	seen := make(map[CtorID]struct{})
+	queue := make([]*Ctor, 0, len(from))
+	for _, c := range from {
+		if _, ok := seen[c.ID]; !ok {
+			seen[c.ID] = struct{}{}
+			queue = append(queue, c)
+		}
+	}
+	for depth := 0; len(queue) > 0; depth++ {
+		var level []*Ctor
+		for _, c := range queue {
+			visit(c)
+			if maxDepth >= 0 && depth >= maxDepth {
+				continue
+			}
+			for _, n := range next(c) {
+				if _, ok := seen[n.ID]; !ok {
+					seen[n.ID] = struct{}{}
+					level = append(level, n)
+				}
+			}
+		}
+		queue = level
+	}
+}
+
+// Retain removes the constructors for which keep returns false from the
+// graph. Groups, decorators and failed nodes that are neither produced nor
+// consumed by the remaining constructors are removed as well.
+//
+// Unlike PruneSuccess, Retain keeps the parameters of the remaining
+// constructors even if the constructors producing them were removed, so
+// that the edges at the boundary of the retained graph stay visible.
+func (dg *Graph) Retain(keep func(*Ctor) bool) {
+	var pruned []*Ctor
+	for _, c := range dg.Ctors {
+		if keep(c) {
+			pruned = append(pruned, c)
+			continue
+		}
+		dg.pruneGroupResults(c, dg.groupMap)
+		delete(dg.ctorMap, c.ID)
+	}
+	dg.Ctors = pruned
+
+	// Values that the remaining constructors produce or consume.
+	used := make(map[nodeKey]struct{})
+	for _, c := range dg.Ctors {
+		for _, r := range c.Results {
+			used[r.nodeKey()] = struct{}{}
+		}
+		for _, p := range c.Params {
+			used[p.nodeKey()] = struct{}{}
+		}
+		for _, g := range c.GroupParams {
+			used[g.nodeKey()] = struct{}{}
+		}
+	}
+
+	groups := make(map[nodeKey]struct{})
+	for _, g := range dg.Groups {
+		if _, ok := used[g.nodeKey()]; ok {
+			groups[g.nodeKey()] = struct{}{}
+		}
+	}
+	dg.pruneGroups(groups)
+
+	var decorators []*Decorator
+	for _, d := range dg.Decorators {
+		for _, r := range d.Results {
+			k := r.nodeKey()
+			if k.group != "" && k.t.Kind() == reflect.Slice {
+				// Decorators of value groups decorate the whole slice.
+				k.t = k.t.Elem()
+			}
+			if _, ok := used[k]; ok {
+				decorators = append(decorators, d)
+				break
+			}
+		}
+	}
+	dg.Decorators = decorators
+
+	retainResults := func(results []*Result) []*Result {
+		var out []*Result
+		for _, r := range results {
+			if _, ok := used[r.nodeKey()]; ok {
+				out = append(out, r)
+			}
+		}
+		return out
+	}
+	dg.Failed.RootCauses = retainResults(dg.Failed.RootCauses)
+	dg.Failed.TransitiveFailures = retainResults(dg.Failed.TransitiveFailures)
+}
+
 // String implements fmt.Stringer for Param.
 func (p *Param) String() string {
 	if p.Name != "" {
@@ -445,6 +728,13 @@ func (g *Group) Attributes() string {
 	return attr
 }
 
+// IsRootCause reports whether the node is the root cause of a failure.
+func (s ErrorType) IsRootCause() bool { return s == rootCause }
+
+// IsTransitiveFailure reports whether the node failed because of one of
+// its dependencies.
+func (s ErrorType) IsTransitiveFailure() bool { return s == transitiveFailure }
+
 // Color returns the color representation of each ErrorType.
 func (s ErrorType) Color() string {
 	switch s {
@@ -458,9 +748,26 @@ func (s ErrorType) Color() string {
 }
 
 func (dg *Graph) addRootCause(r *Result) {
-	dg.Failed.RootCauses = append(dg.Failed.RootCauses, r)
+	dg.Failed.chainHasRootCause = true
+	if !containsResult(dg.Failed.RootCauses, r) {
+		dg.Failed.RootCauses = append(dg.Failed.RootCauses, r)
+	}
 }
 
 func (dg *Graph) addTransitiveFailure(r *Result) {
-	dg.Failed.TransitiveFailures = append(dg.Failed.TransitiveFailures, r)
+	// Chains of failures may share nodes. Each node is only listed once.
+	if !containsResult(dg.Failed.RootCauses, r) && !containsResult(dg.Failed.TransitiveFailures, r) {
+		dg.Failed.TransitiveFailures = append(dg.Failed.TransitiveFailures, r)
+	}
+}
+
+// containsResult reports whether results has a Result for the same node
+// as r.
+func containsResult(results []*Result, r *Result) bool {
+	for _, o := range results {
+		if o.String() == r.String() {
+			return true
+		}
+	}
+	return false
 }
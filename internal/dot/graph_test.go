@@ -25,6 +25,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type (
@@ -92,6 +93,17 @@ func TestAddCtor(t *testing.T) {
 		assert.Equal(t, map[nodeKey]*Group{k: expectedGroup}, dg.groupMap)
 	})
 
+	t.Run("soft grouped params", func(t *testing.T) {
+		dg := NewGraph()
+		c := &Ctor{ID: 1234}
+		soft := &Param{Node: &Node{Type: type3, Group: "soft"}, Soft: true}
+		dg.AddCtor(c, []*Param{p3, soft}, []*Result{})
+
+		require.Len(t, c.GroupParams, 2)
+		assert.False(t, c.IsSoftGroupParam(c.GroupParams[0]))
+		assert.True(t, c.IsSoftGroupParam(c.GroupParams[1]))
+	})
+
 	t.Run("grouped results", func(t *testing.T) {
 		dg := NewGraph()
 		c0 := &Ctor{ID: 1234}
@@ -127,6 +139,25 @@ func TestAddCtor(t *testing.T) {
 	})
 }
 
+func TestAddDecoratorAndScope(t *testing.T) {
+	dg := NewGraph()
+	assert.Equal(t, 0, dg.AddScope("", -1))
+	assert.Equal(t, 1, dg.AddScope("child", 0))
+	assert.Equal(t, []*Scope{
+		{ID: 0, Name: "", Parent: -1},
+		{ID: 1, Name: "child", Parent: 0},
+	}, dg.Scopes)
+
+	p := &Param{Node: &Node{Type: reflect.TypeOf(t1{})}}
+	r := &Result{Node: &Node{Type: reflect.TypeOf(t1{})}}
+	d := &Decorator{ID: 123, Scope: 1}
+	dg.AddDecorator(d, []*Param{p}, []*Result{r})
+	assert.Equal(t, []*Decorator{d}, dg.Decorators)
+	assert.Equal(t, []*Param{p}, d.Params)
+	assert.Equal(t, []*Result{r}, d.Results)
+	assert.Empty(t, dg.Ctors)
+}
+
 func TestFailNodes(t *testing.T) {
 	type1 := reflect.TypeOf(&t1{})
 	type2 := reflect.TypeOf(&t2{})
@@ -196,6 +227,32 @@ func TestFailNodes(t *testing.T) {
 		assert.Equal(t, transitiveFailure, c1.ErrorType)
 		assert.Equal(t, transitiveFailure, dg.groupMap[k1].ErrorType)
 	})
+
+	t.Run("failure chains", func(t *testing.T) {
+		dg := NewGraph()
+		c0 := &Ctor{ID: 123}
+		c1 := &Ctor{ID: 456}
+		c2 := &Ctor{ID: 789}
+
+		dg.AddCtor(c0, []*Param{}, []*Result{r1})
+		dg.AddCtor(c1, []*Param{}, []*Result{r2})
+		dg.AddCtor(c2, []*Param{}, []*Result{r3})
+
+		dg.StartFailureChain()
+		dg.FailNodes([]*Result{r1}, 123)
+		dg.FailNodes([]*Result{r3}, 789)
+
+		// Chains may share their nodes. These are only listed once.
+		dg.StartFailureChain()
+		dg.FailNodes([]*Result{r2}, 456)
+		dg.FailNodes([]*Result{{Node: n3}}, 789)
+
+		assert.Equal(t, []*Result{r1, r2}, dg.Failed.RootCauses)
+		assert.Equal(t, []*Result{r3}, dg.Failed.TransitiveFailures)
+		assert.Equal(t, rootCause, c0.ErrorType)
+		assert.Equal(t, rootCause, c1.ErrorType)
+		assert.Equal(t, transitiveFailure, c2.ErrorType)
+	})
 }
 
 func TestPruneSuccess(t *testing.T) {
@@ -376,6 +433,89 @@ func TestPruneSuccess(t *testing.T) {
 	})
 }
 
+func TestWalk(t *testing.T) {
+	type1 := reflect.TypeOf(t1{})
+	type2 := reflect.TypeOf(t2{})
+	type3 := reflect.TypeOf(t3{})
+
+	// c1 -> t1 -> c2 -> t2 -> c3 -> t3, plus c4 that adds t1 to a group
+	// consumed by c3.
+	dg := NewGraph()
+	c1 := &Ctor{ID: 1}
+	c2 := &Ctor{ID: 2}
+	c3 := &Ctor{ID: 3}
+	c4 := &Ctor{ID: 4}
+	dg.AddCtor(c1, nil, []*Result{{Node: &Node{Type: type1}}})
+	dg.AddCtor(c2, []*Param{{Node: &Node{Type: type1}}}, []*Result{{Node: &Node{Type: type2}}})
+	dg.AddCtor(c4, nil, []*Result{{Node: &Node{Type: type1, Group: "g"}}})
+	dg.AddCtor(c3, []*Param{
+		{Node: &Node{Type: type2}},
+		{Node: &Node{Type: reflect.SliceOf(type1), Group: "g"}},
+	}, []*Result{{Node: &Node{Type: type3}}})
+
+	walk := func(from []*Ctor, reverse bool, maxDepth int) []CtorID {
+		var ids []CtorID
+		dg.Walk(from, reverse, maxDepth, func(c *Ctor) {
+			ids = append(ids, c.ID)
+		})
+		return ids
+	}
+
+	t.Run("dependencies", func(t *testing.T) {
+		assert.Equal(t, []CtorID{3, 2, 4, 1}, walk([]*Ctor{c3}, false, -1))
+	})
+
+	t.Run("dependents", func(t *testing.T) {
+		assert.Equal(t, []CtorID{1, 2, 3}, walk([]*Ctor{c1}, true, -1))
+		assert.Equal(t, []CtorID{4, 3}, walk([]*Ctor{c4}, true, -1))
+	})
+
+	t.Run("max depth", func(t *testing.T) {
+		assert.Equal(t, []CtorID{3}, walk([]*Ctor{c3}, false, 0))
+		assert.Equal(t, []CtorID{3, 2, 4}, walk([]*Ctor{c3}, false, 1))
+	})
+
+	t.Run("providers and consumers", func(t *testing.T) {
+		assert.Equal(t, []*Ctor{c1, c4}, dg.Providers(type1))
+		assert.Equal(t, []*Ctor{c2, c3}, dg.Consumers(type1))
+		assert.Empty(t, dg.Providers(reflect.TypeOf(0)))
+	})
+}
+
+func TestRetain(t *testing.T) {
+	type1 := reflect.TypeOf(t1{})
+	type2 := reflect.TypeOf(t2{})
+	type3 := reflect.TypeOf(t3{})
+
+	dg := NewGraph()
+	c1 := &Ctor{ID: 1}
+	c2 := &Ctor{ID: 2}
+	c3 := &Ctor{ID: 3}
+	r1 := &Result{Node: &Node{Type: type1, Group: "g"}}
+	r3 := &Result{Node: &Node{Type: type3}}
+	dg.AddCtor(c1, nil, []*Result{r1})
+	dg.AddCtor(c2, []*Param{{Node: &Node{Type: reflect.SliceOf(type1), Group: "g"}}}, []*Result{{Node: &Node{Type: type2}}})
+	dg.AddCtor(c3, nil, []*Result{r3})
+	dg.AddDecorator(&Decorator{ID: 4}, nil, []*Result{{Node: &Node{Type: reflect.SliceOf(type1), Group: "g"}}})
+	dg.AddDecorator(&Decorator{ID: 5}, nil, []*Result{{Node: &Node{Type: type3}}})
+	dg.AddMissingNodes([]*Result{r3})
+
+	dg.Retain(func(c *Ctor) bool { return c.ID == 2 })
+
+	require.Len(t, dg.Ctors, 1)
+	assert.Equal(t, CtorID(2), dg.Ctors[0].ID)
+
+	// The group is still consumed by c2, but c1 is gone.
+	require.Len(t, dg.Groups, 1)
+	assert.Empty(t, dg.Groups[0].Results)
+	assert.Len(t, dg.Ctors[0].GroupParams, 1)
+
+	require.Len(t, dg.Decorators, 1)
+	assert.Equal(t, CtorID(4), dg.Decorators[0].ID)
+
+	assert.Empty(t, dg.Failed.RootCauses)
+}
+
 func TestGetGroup(t *testing.T) {
 	type1 := reflect.TypeOf(t1{})
 	type2 := reflect.TypeOf(t2{})
@@ -455,3 +595,12 @@ func TestColor(t *testing.T) {
 	assert.Equal(t, "red", rootCause.Color())
 	assert.Equal(t, "orange", transitiveFailure.Color())
 }
+
+func TestErrorType(t *testing.T) {
+	assert.False(t, noError.IsRootCause())
+	assert.False(t, noError.IsTransitiveFailure())
+	assert.True(t, rootCause.IsRootCause())
+	assert.False(t, rootCause.IsTransitiveFailure())
+	assert.False(t, transitiveFailure.IsRootCause())
+	assert.True(t, transitiveFailure.IsTransitiveFailure())
+}
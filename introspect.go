@@ -0,0 +1,236 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"go.uber.org/dig/internal/digreflect"
+)
+
+// Location describes where a function was defined.
+type Location struct {
+	// Name of the function.
+	Name string
+
+	// Name of the package in which the function is defined.
+	Package string
+
+	// Path to the file in which the function is defined.
+	File string
+
+	// Line number in the file at which the function is defined.
+	Line int
+}
+
+func newLocation(f *digreflect.Func) Location {
+	if f == nil {
+		return Location{}
+	}
+	return Location{
+		Name:    f.Name,
+		Package: f.Package,
+		File:    f.File,
+		Line:    f.Line,
+	}
+}
+
+// String returns the location in the same format as error messages:
+//
+//	"path/to/package".MyFunction (path/to/file.go:42)
+func (l Location) String() string {
+	return fmt.Sprintf("%q.%v (%v:%v)", l.Package, l.Name, l.File, l.Line)
+}
+
+// ProviderDescriptor describes a constructor provided to a Container.
+type ProviderDescriptor struct {
+	// ID of the constructor. This is the same ID that is reported by
+	// FillProvideInfo.
+	ID ID
+
+	// Location of the constructor.
+	Location Location
+
+	// Scope the constructor was provided to.
+	Scope *Scope
+
+	// Whether the constructor was provided with the Export option.
+	Exported bool
+
+	// Whether the constructor was provided with the Transient option.
+	Transient bool
+
+	// Whether the constructor was provided with the PerScope option.
+	PerScope bool
+
+	// Parameters of the constructor.
+	Inputs []*Input
+
+	// Values produced by the constructor, including the ones it
+	// provides with the As option.
+	Outputs []*Output
+
+	// Interfaces the constructor provides its values as, if it was
+	// provided with the As option.
+	As []reflect.Type
+
+	// Whether the constructor was called, or is being called, to build
+	// its values. This is never true for transient constructors.
+	Instantiated bool
+}
+
+// DecoratorDescriptor describes a decorator registered with a Container.
+type DecoratorDescriptor struct {
+	// ID of the decorator. This is the same ID that is reported by
+	// FillDecorateInfo.
+	ID ID
+
+	// Location of the decorator.
+	Location Location
+
+	// Scope the decorator was registered with.
+	Scope *Scope
+
+	// Parameters of the decorator.
+	Inputs []*Input
+
+	// Values produced by the decorator.
+	Outputs []*Output
+
+	// Whether the decorator was called, or is being called.
+	Instantiated bool
+}
+
+// Providers returns descriptions of all the constructors provided to the
+// Container and its Scopes, in the order of the Scope tree and in the order
+// they were provided.
+func (c *Container) Providers() []ProviderDescriptor {
+	return c.scope.Providers()
+}
+
+// Decorators returns descriptions of all the decorators registered with
+// the Container and its Scopes.
+func (c *Container) Decorators() []DecoratorDescriptor {
+	return c.scope.Decorators()
+}
+
+// Children returns the Scopes created directly from the Container.
+func (c *Container) Children() []*Scope {
+	return c.scope.Children()
+}
+
+// Name returns the name of the Scope.
+func (s *Scope) Name() string {
+	return s.name
+}
+
+// Children returns the Scopes created directly from this Scope, in the
+// order they were created.
+func (s *Scope) Children() []*Scope {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]*Scope(nil), s.childScopes...)
+}
+
+// Providers returns descriptions of all the constructors provided to this
+// Scope and its descendants, in the order of the Scope tree and in the
+// order they were provided.
+//
+// Constructors provided with the Export option are reported along with
+// the constructors of the root Container, but their Scope is the one they
+// were provided to.
+func (s *Scope) Providers() []ProviderDescriptor {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	subscopes := make(map[*Scope]struct{})
+	for _, sc := range s.appendSubscopes(nil) {
+		subscopes[sc] = struct{}{}
+	}
+
+	var descs []ProviderDescriptor
+	for _, sc := range s.rootScope().appendSubscopes(nil) {
+		for _, n := range sc.nodes {
+			if _, ok := subscopes[n.OrigScope()]; ok {
+				descs = append(descs, newProviderDescriptor(n))
+			}
+		}
+	}
+	return descs
+}
+
+// Decorators returns descriptions of all the decorators registered with
+// this Scope and its descendants, in the order of the Scope tree.
+func (s *Scope) Decorators() []DecoratorDescriptor {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var descs []DecoratorDescriptor
+	for _, sc := range s.appendSubscopes(nil) {
+		for _, d := range sc.decoratorNodes() {
+			descs = append(descs, DecoratorDescriptor{
+				ID:           ID(d.id),
+				Location:     newLocation(d.location),
+				Scope:        d.s,
+				Inputs:       newInputs(d.params),
+				Outputs:      newOutputs(d.results),
+				Instantiated: d.used(),
+			})
+		}
+	}
+	return descs
+}
+
+// decoratorNodes returns the decorators registered with this Scope, sorted
+// by location. The caller must hold s.mu.
+func (s *Scope) decoratorNodes() []*decoratorNode {
+	// A decorator is registered under all the keys it decorates.
+	seen := make(map[*decoratorNode]struct{})
+	var ds []*decoratorNode
+	for _, d := range s.decorators {
+		if _, ok := seen[d]; !ok {
+			seen[d] = struct{}{}
+			ds = append(ds, d)
+		}
+	}
+	sort.Slice(ds, func(i, j int) bool {
+		return ds[i].location.String() < ds[j].location.String()
+	})
+	return ds
+}
+
+func newProviderDescriptor(n *constructorNode) ProviderDescriptor {
+	return ProviderDescriptor{
+		ID:           ID(n.id),
+		Location:     newLocation(n.location),
+		Scope:        n.OrigScope(),
+		Exported:     n.exported,
+		Transient:    n.transient,
+		PerScope:     n.perScope,
+		Inputs:       newInputs(n.paramList),
+		Outputs:      newOutputs(n.resultList),
+		As:           append([]reflect.Type(nil), n.as...),
+		Instantiated: n.instantiated(),
+	}
+}
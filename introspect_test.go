@@ -0,0 +1,197 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig_test
+
+import (
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/dig"
+	"go.uber.org/dig/internal/digtest"
+)
+
+type introspectBuffer struct{}
+
+func (*introspectBuffer) Read([]byte) (int, error) { return 0, io.EOF }
+
+func TestIntrospection(t *testing.T) {
+	t.Parallel()
+
+	type A struct{}
+	type B struct{}
+
+	t.Run("inputs and outputs", func(t *testing.T) {
+		t.Parallel()
+
+		type params struct {
+			dig.In
+
+			A  *A   `name:"a" optional:"true"`
+			Bs []*B `group:"bs"`
+			L  dig.Lazy[*B]
+		}
+		type results struct {
+			dig.Out
+
+			A *A `name:"out"`
+			B *B `group:"more"`
+		}
+
+		var info dig.ProvideInfo
+		c := digtest.New(t)
+		c.RequireProvide(func(params) results { return results{} }, dig.FillProvideInfo(&info))
+
+		require.Len(t, info.Inputs, 3)
+		assert.Equal(t, reflect.TypeOf(&A{}), info.Inputs[0].Type())
+		assert.Equal(t, "a", info.Inputs[0].Name())
+		assert.Empty(t, info.Inputs[0].Group())
+		assert.True(t, info.Inputs[0].Optional())
+		assert.False(t, info.Inputs[0].Lazy())
+		assert.Equal(t, reflect.TypeOf([]*B{}), info.Inputs[1].Type())
+		assert.Equal(t, "bs", info.Inputs[1].Group())
+		assert.False(t, info.Inputs[1].Optional())
+		assert.Equal(t, reflect.TypeOf(&B{}), info.Inputs[2].Type())
+		assert.True(t, info.Inputs[2].Lazy())
+		assert.Equal(t, "*dig_test.B[lazy]", info.Inputs[2].String())
+
+		require.Len(t, info.Outputs, 2)
+		assert.Equal(t, reflect.TypeOf(&A{}), info.Outputs[0].Type())
+		assert.Equal(t, "out", info.Outputs[0].Name())
+		assert.Equal(t, reflect.TypeOf(&B{}), info.Outputs[1].Type())
+		assert.Equal(t, "more", info.Outputs[1].Group())
+
+		assert.Equal(t, "TestIntrospection.func1.1", info.Location().Name)
+		assert.Contains(t, info.Location().File, "introspect_test.go")
+	})
+
+	t.Run("providers", func(t *testing.T) {
+		t.Parallel()
+
+		newA := func() *A { return &A{} }
+		newReader := func() *introspectBuffer { return &introspectBuffer{} }
+
+		c := digtest.New(t)
+		c.RequireProvide(newA)
+		c.RequireProvide(func(*A) *B { return &B{} }, dig.Transient())
+
+		child := c.Scope("child")
+		child.RequireProvide(newReader, dig.As(new(io.Reader)), dig.Export(true))
+		child.RequireProvide(func() string { return "" }, dig.PerScope())
+		c.RequireInvoke(func(*A, *B) {})
+
+		providers := c.Providers()
+		require.Len(t, providers, 4)
+
+		a := providers[0]
+		assert.Equal(t, "TestIntrospection.func2.1", a.Location.Name)
+		assert.Equal(t, "go.uber.org/dig_test", a.Location.Package)
+		assert.Contains(t, a.Location.File, "introspect_test.go")
+		assert.Contains(t, a.Location.String(), `"go.uber.org/dig_test".TestIntrospection.func2.1 (`)
+		assert.True(t, a.Instantiated)
+		assert.False(t, a.Exported)
+		require.Len(t, a.Outputs, 1)
+		assert.Equal(t, reflect.TypeOf(&A{}), a.Outputs[0].Type())
+
+		b := providers[1]
+		assert.True(t, b.Transient)
+		assert.False(t, b.Instantiated)
+		require.Len(t, b.Inputs, 1)
+
+		r := providers[2]
+		assert.True(t, r.Exported)
+		assert.Equal(t, "child", r.Scope.Name())
+		assert.Equal(t, []reflect.Type{reflect.TypeOf((*io.Reader)(nil)).Elem()}, r.As)
+		assert.False(t, r.Instantiated)
+
+		s := providers[3]
+		assert.True(t, s.PerScope)
+		assert.Equal(t, "child", s.Scope.Name())
+
+		// Scopes only report the constructors provided to them and their
+		// descendants.
+		assert.Len(t, child.Providers(), 2)
+		assert.Empty(t, child.Scope("grandchild").Providers())
+	})
+
+	t.Run("decorators", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() *A { return &A{} })
+		c.RequireProvide(func() *B { return &B{} })
+		c.RequireDecorate(func(a *A, b *B) (*A, *B) { return a, b })
+
+		child := c.Scope("child")
+		child.RequireDecorate(func(a *A) *A { return a })
+
+		decorators := c.Decorators()
+		require.Len(t, decorators, 2)
+		assert.Equal(t, "child", decorators[1].Scope.Name())
+		assert.Len(t, decorators[0].Inputs, 2)
+		assert.Len(t, decorators[0].Outputs, 2)
+		assert.False(t, decorators[0].Instantiated)
+
+		c.RequireInvoke(func(*A) {})
+		decorators = c.Decorators()
+		assert.True(t, decorators[0].Instantiated)
+		assert.False(t, decorators[1].Instantiated)
+
+		assert.Len(t, child.Decorators(), 1)
+	})
+
+	t.Run("scope tree", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		assert.Empty(t, c.Children())
+
+		s1 := c.Scope("s1")
+		c.Scope("s2")
+		s1.Scope("s3")
+
+		children := c.Children()
+		require.Len(t, children, 2)
+		assert.Equal(t, "s1", children[0].Name())
+		assert.Equal(t, "s2", children[1].Name())
+
+		grandchildren := children[0].Children()
+		require.Len(t, grandchildren, 1)
+		assert.Equal(t, "s3", grandchildren[0].Name())
+		assert.Empty(t, grandchildren[0].Children())
+		assert.Len(t, s1.Children(), 1)
+	})
+
+	t.Run("decorate info", func(t *testing.T) {
+		t.Parallel()
+
+		var info dig.DecorateInfo
+		c := digtest.New(t)
+		c.RequireProvide(func() *A { return &A{} })
+		c.RequireDecorate(func(a *A) *A { return a }, dig.FillDecorateInfo(&info))
+
+		assert.Equal(t, "TestIntrospection.func5.2", info.Location().Name)
+		assert.Equal(t, "go.uber.org/dig_test", info.Location().Package)
+		assert.Contains(t, info.Location().File, "introspect_test.go")
+	})
+}
@@ -21,6 +21,7 @@
 package dig
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 
@@ -34,7 +35,8 @@ type InvokeOption interface {
 }
 
 type invokeOptions struct {
-	Info *InvokeInfo
+	Info          *InvokeInfo
+	CollectErrors bool
 }
 
 // InvokeInfo provides information about an Invoke.
@@ -67,6 +69,32 @@ func (o fillInvokeInfoOption) applyInvokeOption(opts *invokeOptions) {
 	opts.Info = o.info
 }
 
+// CollectErrors is an InvokeOption that keeps building the dependencies of
+// the invoked function after one of them failed, so that all the failures
+// are reported at once. Only the values that depend on a failed constructor
+// are skipped, and constructors that failed are not called again during
+// the same Invoke.
+//
+// If more than one dependency failed, the returned error wraps all their
+// errors. They can be inspected with errors.Is and errors.As, rendered with
+// [ErrorTree], or passed to [VisualizeError] to color all the root causes
+// of the failure.
+//
+//	err := c.Invoke(func(*Server) { ... }, dig.CollectErrors())
+func CollectErrors() InvokeOption {
+	return collectErrorsOption{}
+}
+
+type collectErrorsOption struct{}
+
+func (collectErrorsOption) String() string {
+	return "CollectErrors()"
+}
+
+func (collectErrorsOption) applyInvokeOption(opts *invokeOptions) {
+	opts.CollectErrors = true
+}
+
 // Invoke runs the given function after instantiating its dependencies.
 //
 // Any arguments that the function has are treated as its dependencies. The
@@ -83,6 +111,13 @@ func (c *Container) Invoke(function interface{}, opts ...InvokeOption) error {
 	return c.scope.Invoke(function, opts...)
 }
 
+// InvokeContext runs the given function after instantiating its
+// dependencies, like Invoke, with the given context. See
+// Scope.InvokeContext.
+func (c *Container) InvokeContext(ctx context.Context, function interface{}, opts ...InvokeOption) error {
+	return c.scope.InvokeContext(ctx, function, opts...)
+}
+
 // Invoke runs the given function after instantiating its dependencies.
 //
 // Any arguments that the function has are treated as its dependencies. The
@@ -91,7 +126,35 @@ func (c *Container) Invoke(function interface{}, opts ...InvokeOption) error {
 //
 // The function may return an error to indicate failure. The error will be
 // returned to the caller as-is.
-func (s *Scope) Invoke(function interface{}, opts ...InvokeOption) (err error) {
+func (s *Scope) Invoke(function interface{}, opts ...InvokeOption) error {
+	return s.invoke(context.Background(), function, opts)
+}
+
+// InvokeContext runs the given function after instantiating its
+// dependencies, like Invoke, with the given context.
+//
+// Unnamed context.Context parameters of the function, and of the
+// constructors and decorators called to build its dependencies, are
+// satisfied with ctx unless a constructor provides context.Context.
+// Invoke uses context.Background instead.
+//
+// Once ctx is done, no further constructor, decorator or invoked function
+// is called, and InvokeContext returns a [CanceledError] that names the
+// function that was about to be called. Functions that are already running
+// are not interrupted: they should return when the context they were given
+// is done. See [Timeout] to stop waiting for a constructor.
+//
+//	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+//	defer cancel()
+//	err := c.InvokeContext(ctx, func(db *sql.DB) { ... })
+func (s *Scope) InvokeContext(ctx context.Context, function interface{}, opts ...InvokeOption) error {
+	if ctx == nil {
+		return newErrInvalidInput("can't invoke with a nil context", nil)
+	}
+	return s.invoke(ctx, function, opts)
+}
+
+func (s *Scope) invoke(ctx context.Context, function interface{}, opts []InvokeOption) (err error) {
 	ftype := reflect.TypeOf(function)
 	if ftype == nil {
 		return newErrInvalidInput("can't invoke an untyped nil", nil)
@@ -101,11 +164,36 @@ func (s *Scope) Invoke(function interface{}, opts ...InvokeOption) (err error) {
 			fmt.Sprintf("can't invoke non-function %v (type %v)", function, ftype), nil)
 	}
 
+	s.mu.Lock()
 	pl, err := newParamList(ftype, s)
+	s.mu.Unlock()
 	if err != nil {
 		return err
 	}
 
+	if s.emitting() {
+		loc := funcLocation(function)
+		inputs := newInputs(pl)
+		s.emit(Invoking{Location: loc, Scope: s.name, Inputs: inputs})
+
+		start := s.clock().Now()
+		defer func() {
+			s.emit(Invoked{
+				Location: loc,
+				Scope:    s.name,
+				Inputs:   inputs,
+				Runtime:  s.clock().Since(start),
+				Err:      err,
+			})
+		}()
+	}
+
+	var span *traceSpan
+	if s.tracer != nil {
+		span = s.startSpan(nil, SpanInvoke, digreflect.InspectFunc(function))
+		defer func() { span.end(err) }()
+	}
+
 	if err := shallowCheckDependencies(s, pl); err != nil {
 		return errMissingDependencies{
 			Func:   digreflect.InspectFunc(function),
@@ -113,19 +201,32 @@ func (s *Scope) Invoke(function interface{}, opts ...InvokeOption) (err error) {
 		}
 	}
 
-	if !s.isVerifiedAcyclic {
-		if ok, cycle := graph.IsAcyclic(s.gh); !ok {
-			return newErrInvalidInput("cycle detected in dependency graph", s.cycleDetectedError(cycle))
-		}
-		s.isVerifiedAcyclic = true
+	if err := s.verifyAcyclic(); err != nil {
+		return err
+	}
+
+	var options invokeOptions
+	for _, o := range opts {
+		o.applyInvokeOption(&options)
 	}
 
-	args, err := pl.BuildList(s)
+	bs := newBuildState(ctx, s.builds).within(span)
+	defer bs.finish()
+	if options.CollectErrors {
+		bs = bs.collectingErrors()
+	}
+	args, err := pl.BuildList(s, bs)
 	if err != nil {
-		return errArgumentsFailed{
+		err = errArgumentsFailed{
 			Func:   digreflect.InspectFunc(function),
 			Reason: err,
 		}
+	} else if cerr := ctx.Err(); cerr != nil {
+		err = CanceledError{Location: funcLocation(function), Err: cerr}
+	}
+	if err != nil {
+		// Clean up whatever was built for this Invoke before it failed.
+		return bs.rollback(err)
 	}
 	if s.recoverFromPanics {
 		defer func() {
@@ -138,24 +239,9 @@ func (s *Scope) Invoke(function interface{}, opts ...InvokeOption) (err error) {
 		}()
 	}
 
-	var options invokeOptions
-	for _, o := range opts {
-		o.applyInvokeOption(&options)
-	}
-
 	// Record info for the invoke if requested
 	if info := options.Info; info != nil {
-		params := pl.DotParam()
-		info.Inputs = make([]*Input, len(params))
-		for i, p := range params {
-			info.Inputs[i] = &Input{
-				t:        p.Type,
-				optional: p.Optional,
-				name:     p.Name,
-				group:    p.Group,
-			}
-		}
-
+		info.Inputs = newInputs(pl)
 	}
 
 	returned := s.invokerFn(reflect.ValueOf(function), args)
@@ -171,6 +257,22 @@ func (s *Scope) Invoke(function interface{}, opts ...InvokeOption) (err error) {
 	return nil
 }
 
+// verifyAcyclic checks the dependency graph of this Scope for cycles
+// unless it was already verified since it last changed.
+func (s *Scope) verifyAcyclic() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.isVerifiedAcyclic {
+		return nil
+	}
+	if ok, cycle := graph.IsAcyclic(s.gh); !ok {
+		return newErrInvalidInput("cycle detected in dependency graph", s.cycleDetectedError(cycle))
+	}
+	s.isVerifiedAcyclic = true
+	return nil
+}
+
 // Checks that all direct dependencies of the provided parameters are present in
 // the container. Returns an error if not.
 func shallowCheckDependencies(c containerStore, pl paramList) error {
@@ -199,13 +301,16 @@ func findMissingDependencies(c containerStore, params ...param) []paramSingle {
 			// and it is NOT being decorated and is NOT optional.
 			// In the case that there is no providers but there is a decorated value
 			// of this type, it can be provided safely so we can safely skip this.
-			if len(allProviders) == 0 && !hasDecoratedValue && !p.Optional {
+			// The context of the Invoke is used when nothing provides it.
+			if len(allProviders) == 0 && !hasDecoratedValue && !p.Optional && !p.isContext() {
 				missingDeps = append(missingDeps, p)
 			}
 		case paramObject:
 			for _, f := range p.Fields {
 				missingDeps = append(missingDeps, findMissingDependencies(c, f.Param)...)
 			}
+		case paramLazy:
+			missingDeps = append(missingDeps, findMissingDependencies(c, p.Value)...)
 		}
 	}
 	return missingDeps
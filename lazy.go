@@ -0,0 +1,169 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"go.uber.org/dig/internal/dot"
+)
+
+// Lazy is a dependency on a value of type T that is built only when Get is
+// called, rather than before the function that depends on it runs.
+//
+//	func NewReporter(db dig.Lazy[*sql.DB]) *Reporter {
+//	  return &Reporter{db: db}
+//	}
+//
+//	func (r *Reporter) Report() error {
+//	  db, err := r.db.Get()
+//	  if err != nil {
+//	    return err
+//	  }
+//	  // ...
+//	}
+//
+// Lazy dependencies are not considered when the container checks for
+// cycles. This allows two constructors to depend on each other as long as
+// one of them does so lazily and does not call Get while it is being
+// constructed. Doing so fails with an error instead.
+//
+// Lazy may be used as a field of a parameter object, with the name and
+// optional tags applying to the value of type T.
+//
+//	type Params struct {
+//	  dig.In
+//
+//	  Cache dig.Lazy[*Cache] `name:"shared" optional:"true"`
+//	}
+//
+// Get returns the value stored in the container once it was built. For
+// values provided with Transient, every call to Get builds a new value.
+type Lazy[T any] struct {
+	build func() (reflect.Value, error)
+}
+
+// Get builds the value, if needed, and returns it.
+func (l Lazy[T]) Get() (T, error) {
+	var zero T
+	if l.build == nil {
+		return zero, errors.New("dig.Lazy was not provided by a container")
+	}
+
+	v, err := l.build()
+	if err != nil {
+		return zero, err
+	}
+	return valueAs[T](v), nil
+}
+
+// MustGet is like Get but panics if the value cannot be built.
+func (l Lazy[T]) MustGet() T {
+	v, err := l.Get()
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (Lazy[T]) lazyElem() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func (Lazy[T]) withBuild(build func() (reflect.Value, error)) interface{} {
+	return Lazy[T]{build: build}
+}
+
+// lazy is implemented by all instantiations of Lazy.
+type lazy interface {
+	// lazyElem returns the type of the value to build.
+	lazyElem() reflect.Type
+
+	// withBuild returns a Lazy that builds its value with the given
+	// function.
+	withBuild(func() (reflect.Value, error)) interface{}
+}
+
+var _lazyType = reflect.TypeOf((*lazy)(nil)).Elem()
+
+// isLazy reports whether t is an instantiation of Lazy.
+func isLazy(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || !t.Implements(_lazyType) {
+		return false
+	}
+	// Rule out structs that embed a Lazy.
+	l := reflect.Zero(t).Interface().(lazy)
+	return reflect.TypeOf(l.withBuild(nil)) == t
+}
+
+// paramLazy is a Lazy[T] requested by a function. Value is the param for
+// T, which is built only when the Lazy is used.
+type paramLazy struct {
+	Type  reflect.Type
+	Value param
+}
+
+var _ param = paramLazy{}
+
+func newParamLazy(t reflect.Type, c containerStore) (paramLazy, error) {
+	l := reflect.Zero(t).Interface().(lazy)
+	p, err := newParam(l.lazyElem(), c)
+	if err != nil {
+		return paramLazy{}, err
+	}
+	return paramLazy{Type: t, Value: p}, nil
+}
+
+func (pl paramLazy) String() string {
+	return fmt.Sprintf("lazy %v", pl.Value)
+}
+
+func (pl paramLazy) DotParam() []*dot.Param {
+	params := pl.Value.DotParam()
+	for _, p := range params {
+		p.Lazy = true
+	}
+	return params
+}
+
+// Build returns a Lazy that builds the value from c when it's used. The
+// Lazy keeps track of the constructors that were running when it was
+// built so that using it before they're done is reported as a cycle.
+func (pl paramLazy) Build(c containerStore, bs *buildState) (reflect.Value, error) {
+	l := reflect.Zero(pl.Type).Interface().(lazy)
+	return reflect.ValueOf(l.withBuild(func() (reflect.Value, error) {
+		// Providers may have been added since the Lazy was built.
+		if err := c.asScope().verifyAcyclic(); err != nil {
+			return _noValue, err
+		}
+		// The build the Lazy was made in may be over by now. Its
+		// context and log no longer apply then, so build with a fresh
+		// state that only tracks the constructors that were running.
+		state := bs
+		if state.finished() {
+			state = state.detached(c.asScope().builds)
+			defer state.finish()
+		}
+		return pl.Value.Build(c, state)
+	})), nil
+}
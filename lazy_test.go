@@ -0,0 +1,243 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/dig"
+	"go.uber.org/dig/internal/digtest"
+)
+
+func TestLazy(t *testing.T) {
+	t.Parallel()
+
+	type A struct{}
+	type B struct{}
+
+	t.Run("built on first use", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int
+		c := digtest.New(t)
+		c.RequireProvide(func() *A {
+			calls++
+			return &A{}
+		})
+
+		var lazy dig.Lazy[*A]
+		c.RequireInvoke(func(l dig.Lazy[*A]) { lazy = l })
+		assert.Equal(t, 0, calls)
+
+		a1, err := lazy.Get()
+		require.NoError(t, err)
+		a2 := lazy.MustGet()
+		assert.Same(t, a1, a2)
+		assert.Equal(t, 1, calls)
+
+		c.RequireInvoke(func(a *A) { assert.Same(t, a1, a) })
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("breaks cycles", func(t *testing.T) {
+		t.Parallel()
+
+		type Handler struct{ server interface{} }
+		type Server struct{ handler dig.Lazy[*Handler] }
+
+		c := digtest.New(t)
+		c.RequireProvide(func(h dig.Lazy[*Handler]) *Server {
+			return &Server{handler: h}
+		})
+		c.RequireProvide(func(s *Server) *Handler {
+			return &Handler{server: s}
+		})
+
+		c.RequireInvoke(func(s *Server) {
+			h := s.handler.MustGet()
+			assert.Same(t, s, h.server)
+		})
+	})
+
+	t.Run("Get during construction of a cycle", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func(l dig.Lazy[*B]) (*A, error) {
+			_, err := l.Get()
+			return &A{}, err
+		})
+		c.RequireProvide(func(*A) *B { return &B{} })
+
+		err := c.Invoke(func(*A) {})
+		require.Error(t, err)
+		assert.True(t, dig.IsCycleDetected(err), "expected a cycle error, got %v", err)
+		assert.Contains(t, err.Error(), "could not build arguments for function")
+		assert.Contains(t, err.Error(), "depends on func(*dig_test.A) *dig_test.B provided by")
+	})
+
+	t.Run("transient cycle", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func(l dig.Lazy[*A]) (*A, error) {
+			_, err := l.Get()
+			return &A{}, err
+		}, dig.Transient())
+
+		err := c.Invoke(func(*A) {})
+		require.Error(t, err)
+		assert.True(t, dig.IsCycleDetected(err), "expected a cycle error, got %v", err)
+	})
+
+	t.Run("parameter objects", func(t *testing.T) {
+		t.Parallel()
+
+		type params struct {
+			dig.In
+
+			Named    dig.Lazy[*A] `name:"named"`
+			Optional dig.Lazy[*B] `optional:"true"`
+		}
+
+		c := digtest.New(t)
+		c.RequireProvide(func() *A { return &A{} }, dig.Name("named"))
+
+		c.RequireInvoke(func(p params) {
+			a, err := p.Named.Get()
+			require.NoError(t, err)
+			assert.NotNil(t, a)
+
+			b, err := p.Optional.Get()
+			require.NoError(t, err)
+			assert.Nil(t, b)
+		})
+	})
+
+	t.Run("missing dependency", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		err := c.Invoke(func(dig.Lazy[*A]) {})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing type: *dig_test.A")
+	})
+
+	t.Run("construction error", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() (*A, error) {
+			return nil, errors.New("great sadness")
+		})
+		c.RequireInvoke(func(l dig.Lazy[*A]) {
+			_, err := l.Get()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "great sadness")
+			assert.Panics(t, func() { l.MustGet() })
+		})
+	})
+
+	t.Run("zero value", func(t *testing.T) {
+		t.Parallel()
+
+		var l dig.Lazy[*A]
+		_, err := l.Get()
+		assert.ErrorContains(t, err, "dig.Lazy was not provided by a container")
+	})
+
+	t.Run("nil interface", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() io.Reader { return nil })
+		c.RequireInvoke(func(l dig.Lazy[io.Reader]) {
+			r, err := l.Get()
+			require.NoError(t, err)
+			assert.Nil(t, r)
+		})
+	})
+
+	t.Run("embedded Lazy is not lazy", func(t *testing.T) {
+		t.Parallel()
+
+		type wrapper struct{ dig.Lazy[*A] }
+
+		c := digtest.New(t)
+		c.RequireProvide(func() *A { return &A{} })
+		err := c.Invoke(func(wrapper) {})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing type: dig_test.wrapper")
+	})
+
+	t.Run("Get after the Invoke is over", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func(ctx context.Context) (*A, error) {
+			return &A{}, ctx.Err()
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		var lazy dig.Lazy[*A]
+		require.NoError(t, c.InvokeContext(ctx, func(l dig.Lazy[*A]) { lazy = l }))
+		cancel()
+
+		a, err := lazy.Get()
+		require.NoError(t, err, "Get must not use the context of the Invoke")
+		assert.NotNil(t, a)
+	})
+
+	t.Run("Get after a Timeout constructor returned", func(t *testing.T) {
+		t.Parallel()
+
+		type Server struct{ handler dig.Lazy[*A] }
+
+		c := digtest.New(t)
+		c.RequireProvide(func(ctx context.Context) (*A, error) {
+			return &A{}, ctx.Err()
+		})
+		c.RequireProvide(func(l dig.Lazy[*A]) *Server {
+			return &Server{handler: l}
+		}, dig.Timeout(time.Minute))
+
+		c.RequireInvoke(func(s *Server) {
+			assert.NotNil(t, s.handler.MustGet())
+		})
+	})
+
+	t.Run("Resolve", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() *A { return &A{} })
+
+		l, err := dig.Resolve[dig.Lazy[*A]](c)
+		require.NoError(t, err)
+		assert.NotNil(t, l.MustGet())
+	})
+}
@@ -0,0 +1,251 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"go.uber.org/dig/internal/dot"
+)
+
+// mermaidWriter renders a dot.Graph as a Mermaid flowchart.
+//
+// Unlike DOT, Mermaid cannot use arbitrary strings as node IDs, so nodes
+// are given generated IDs in the order they are first seen.
+type mermaidWriter struct {
+	*scopeTree
+
+	w   io.Writer
+	ids map[string]string // node key to ID
+}
+
+func visualizeMermaid(w io.Writer, dg *dot.Graph) {
+	m := mermaidWriter{scopeTree: newScopeTree(dg), w: w, ids: make(map[string]string)}
+
+	io.WriteString(w, "flowchart RL\n")
+	for _, g := range dg.Groups {
+		fmt.Fprintf(w, "\t%s{%s}\n", m.id(g.String()), mermaidLabel(g.Type.String(), "Group: "+g.Name))
+	}
+
+	// Constructors and decorators of child Scopes are written in nested
+	// subgraphs, one per Scope.
+	m.writeClusters(0, "\t")
+	for _, child := range m.children[0] {
+		if !m.empty(child) {
+			m.writeScope(child, "\t")
+		}
+	}
+
+	// Parameters and failures that are not produced by any constructor
+	// still need a node of their own.
+	for _, c := range dg.Ctors {
+		for _, p := range c.Params {
+			m.declare(m.target(c.Scope, p.String()), mermaidLabel(p.String()))
+		}
+	}
+	for _, d := range dg.Decorators {
+		for _, p := range d.Params {
+			m.declare(m.decoratorTarget(d, p), mermaidLabel(p.String()))
+		}
+	}
+	for _, f := range dg.Failed.TransitiveFailures {
+		m.declare(f.String(), mermaidResultLabel(f))
+	}
+	for _, f := range dg.Failed.RootCauses {
+		m.declare(f.String(), mermaidResultLabel(f))
+	}
+
+	for _, g := range dg.Groups {
+		for _, r := range g.Results {
+			fmt.Fprintf(w, "\t%s --> %s\n", m.id(g.String()), m.id(r.String()))
+		}
+	}
+	for idx, c := range dg.Ctors {
+		for _, p := range c.Params {
+			fmt.Fprintf(w, "\tconstructor_%d %s %s\n", idx, mermaidArrow(p), m.id(m.target(c.Scope, p.String())))
+		}
+		for _, g := range c.GroupParams {
+			fmt.Fprintf(w, "\tconstructor_%d --> %s\n", idx, m.id(m.target(c.Scope, g.String())))
+		}
+	}
+	for idx, d := range dg.Decorators {
+		for _, p := range d.Params {
+			fmt.Fprintf(w, "\tdecorator_%d %s %s\n", idx, mermaidArrow(p), m.id(m.decoratorTarget(d, p)))
+		}
+	}
+
+	for _, g := range dg.Groups {
+		if g.ErrorType.IsRootCause() || g.ErrorType.IsTransitiveFailure() {
+			fmt.Fprintf(w, "\tstyle %s stroke:%s\n", m.id(g.String()), g.ErrorType.Color())
+		}
+	}
+	if len(dg.Failed.TransitiveFailures) > 0 || len(dg.Failed.RootCauses) > 0 {
+		io.WriteString(w, "\tclassDef transitiveFailure stroke:orange\n")
+		io.WriteString(w, "\tclassDef rootCause stroke:red\n")
+	}
+	for _, f := range dg.Failed.TransitiveFailures {
+		fmt.Fprintf(w, "\tclass %s transitiveFailure\n", m.id(f.String()))
+	}
+	for _, f := range dg.Failed.RootCauses {
+		fmt.Fprintf(w, "\tclass %s rootCause\n", m.id(f.String()))
+	}
+}
+
+// writeScope writes the subgraph of a child Scope, nested subgraphs
+// included, at the given indentation.
+func (m *mermaidWriter) writeScope(id int, indent string) {
+	fmt.Fprintf(m.w, "%ssubgraph cluster_scope_%d [%s]\n", indent, id, mermaidLabel(m.dg.Scopes[id].Name))
+	m.writeClusters(id, indent+"\t")
+	for _, child := range m.children[id] {
+		if !m.empty(child) {
+			m.writeScope(child, indent+"\t")
+		}
+	}
+	fmt.Fprintf(m.w, "%send\n", indent)
+}
+
+// writeClusters writes the subgraphs of the constructors and decorators of
+// the Scope with the given ID.
+func (m *mermaidWriter) writeClusters(scope int, indent string) {
+	for idx, c := range m.dg.Ctors {
+		if c.Scope == scope {
+			m.writeCtor(indent, idx, c)
+		}
+	}
+	for idx, d := range m.dg.Decorators {
+		if d.Scope == scope {
+			m.writeDecorator(indent, idx, d)
+		}
+	}
+}
+
+func (m *mermaidWriter) writeCtor(indent string, idx int, c *dot.Ctor) {
+	label := []string{c.Name}
+	if c.Exported {
+		label = append(label, "(exported)")
+	}
+	fmt.Fprintf(m.w, "%ssubgraph cluster_%d [%s]\n", indent, idx, mermaidLabel(mermaidTitle(c.Package)))
+	fmt.Fprintf(m.w, "%s\tconstructor_%d[%s]\n", indent, idx, mermaidLabel(label...))
+	for _, r := range c.Results {
+		m.declareIn(indent+"\t", r.String(), mermaidResultLabel(r))
+	}
+	fmt.Fprintf(m.w, "%send\n", indent)
+
+	var styles []string
+	if c.ErrorType.IsRootCause() || c.ErrorType.IsTransitiveFailure() {
+		styles = append(styles, "stroke:"+c.ErrorType.Color())
+	}
+	if c.Transient {
+		styles = append(styles, "stroke-dasharray:5 5")
+	}
+	if c.Exported {
+		styles = append(styles, "stroke-width:3px")
+	}
+	if len(styles) > 0 {
+		fmt.Fprintf(m.w, "%sstyle cluster_%d %s\n", indent, idx, strings.Join(styles, ","))
+	}
+}
+
+func (m *mermaidWriter) writeDecorator(indent string, idx int, d *dot.Decorator) {
+	fmt.Fprintf(m.w, "%ssubgraph cluster_decorator_%d [%s]\n", indent, idx, mermaidLabel(mermaidTitle(d.Package)))
+	fmt.Fprintf(m.w, "%s\tdecorator_%d[%s]\n", indent, idx, mermaidLabel(d.Name))
+	for _, r := range d.Results {
+		id := m.decorated[dotScopedKey{d.Scope, dotNodeKey(r.Node)}]
+		if r.Group != "" {
+			fmt.Fprintf(m.w, "%s\t%s{%s}\n", indent, m.id(id), mermaidLabel(r.Type.Elem().String(), "Group: "+r.Group))
+		} else {
+			m.declareIn(indent+"\t", id, mermaidResultLabel(r))
+		}
+	}
+	fmt.Fprintf(m.w, "%send\n", indent)
+	fmt.Fprintf(m.w, "%sstyle cluster_decorator_%d fill:lightgrey\n", indent, idx)
+}
+
+// id returns the ID of the node with the given key, assigning one if
+// necessary.
+func (m *mermaidWriter) id(key string) string {
+	id, ok := m.ids[key]
+	if !ok {
+		id = fmt.Sprintf("node_%d", len(m.ids))
+		m.ids[key] = id
+	}
+	return id
+}
+
+// declare writes an oval node with the given key and label unless it was
+// already written.
+func (m *mermaidWriter) declare(key, label string) {
+	m.declareIn("\t", key, label)
+}
+
+func (m *mermaidWriter) declareIn(indent, key, label string) {
+	if _, ok := m.ids[key]; ok {
+		return
+	}
+	fmt.Fprintf(m.w, "%s%s([%s])\n", indent, m.id(key), label)
+}
+
+// mermaidTitle returns the title of a subgraph for the given package.
+// Mermaid requires subgraph titles to be non-empty.
+func mermaidTitle(pkg string) string {
+	if pkg == "" {
+		return " "
+	}
+	return pkg
+}
+
+func mermaidArrow(p *dot.Param) string {
+	switch {
+	case p.Optional:
+		return "-.->"
+	case p.Lazy:
+		return "-. lazy .->"
+	default:
+		return "-->"
+	}
+}
+
+func mermaidResultLabel(r *dot.Result) string {
+	switch {
+	case r.Name != "":
+		return mermaidLabel(r.Type.String(), "Name: "+r.Name)
+	case r.Group != "":
+		return mermaidLabel(r.Type.String(), "Group: "+r.Group)
+	default:
+		return mermaidLabel(r.Type.String())
+	}
+}
+
+var _mermaidEscaper = strings.NewReplacer(
+	`"`, "#quot;",
+	"<", "#lt;",
+	">", "#gt;",
+)
+
+// mermaidLabel builds a quoted Mermaid label with one line per argument.
+func mermaidLabel(lines ...string) string {
+	for i, l := range lines {
+		lines[i] = _mermaidEscaper.Replace(l)
+	}
+	return `"` + strings.Join(lines, "<br/>") + `"`
+}
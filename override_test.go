@@ -0,0 +1,287 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/dig"
+	"go.uber.org/dig/internal/digtest"
+)
+
+func TestOverride(t *testing.T) {
+	t.Parallel()
+
+	type A struct{ name string }
+	type B struct{ name string }
+
+	t.Run("replaces provider", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() *A { return &A{name: "real"} })
+		c.RequireProvide(func() *A { return &A{name: "fake"} }, dig.Override())
+		c.RequireInvoke(func(a *A) {
+			assert.Equal(t, "fake", a.name)
+		})
+	})
+
+	t.Run("Replace", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() *A { return &A{name: "real"} })
+		require.NoError(t, c.Replace(func() *A { return &A{name: "fake"} }))
+		c.RequireInvoke(func(a *A) {
+			assert.Equal(t, "fake", a.name)
+		})
+
+		s := c.Scope("child")
+		s.RequireProvide(func() *B { return &B{name: "real"} })
+		require.NoError(t, s.Replace(func() *B { return &B{name: "fake"} }))
+		s.RequireInvoke(func(b *B) {
+			assert.Equal(t, "fake", b.name)
+		})
+	})
+
+	t.Run("without existing provider", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() *A { return &A{name: "fake"} }, dig.Override())
+		c.RequireInvoke(func(a *A) {
+			assert.Equal(t, "fake", a.name)
+		})
+	})
+
+	t.Run("other results are kept", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() (*A, *B) {
+			return &A{name: "real"}, &B{name: "real"}
+		})
+		c.RequireProvide(func() *A { return &A{name: "fake"} }, dig.Override())
+		c.RequireInvoke(func(a *A, b *B) {
+			assert.Equal(t, "fake", a.name)
+			assert.Equal(t, "real", b.name)
+		})
+	})
+
+	t.Run("other results are built first", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() (*A, *B) {
+			return &A{name: "real"}, &B{name: "real"}
+		})
+		c.RequireProvide(func() *A { return &A{name: "fake"} }, dig.Override())
+		clone := c.Clone()
+
+		c.RequireInvoke(func(b *B) {
+			assert.Equal(t, "real", b.name)
+		})
+		c.RequireInvoke(func(a *A) {
+			assert.Equal(t, "fake", a.name)
+		})
+
+		require.NoError(t, clone.Invoke(func(*B) {}))
+		require.NoError(t, clone.Invoke(func(a *A) {
+			assert.Equal(t, "fake", a.name)
+		}))
+	})
+
+	t.Run("override in a clone", func(t *testing.T) {
+		t.Parallel()
+
+		type C struct{ name string }
+
+		c := digtest.New(t)
+		c.RequireProvide(func() (*A, *B, *C) {
+			return &A{name: "real"}, &B{name: "real"}, &C{name: "real"}
+		})
+		c.RequireProvide(func() *A { return &A{name: "fake"} }, dig.Override())
+
+		clone := c.Clone()
+		require.NoError(t, clone.Provide(func() *B { return &B{name: "fake"} }, dig.Override()))
+
+		c.RequireInvoke(func(a *A, b *B, c *C) {
+			assert.Equal(t, "fake", a.name)
+			assert.Equal(t, "real", b.name, "override in the clone must not affect the original")
+			assert.Equal(t, "real", c.name)
+		})
+		require.NoError(t, clone.Invoke(func(a *A, b *B, c *C) {
+			assert.Equal(t, "fake", a.name)
+			assert.Equal(t, "fake", b.name)
+			assert.Equal(t, "real", c.name)
+		}))
+	})
+
+	t.Run("named values", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() *A { return &A{name: "real"} }, dig.Name("a"))
+		c.RequireProvide(func() *A { return &A{name: "unnamed"} })
+		c.RequireProvide(func() *A { return &A{name: "fake"} }, dig.Name("a"), dig.Override())
+
+		type params struct {
+			dig.In
+
+			Named   *A `name:"a"`
+			Unnamed *A
+		}
+		c.RequireInvoke(func(p params) {
+			assert.Equal(t, "fake", p.Named.name)
+			assert.Equal(t, "unnamed", p.Unnamed.name)
+		})
+	})
+
+	t.Run("value groups are appended to", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() *A { return &A{name: "1"} }, dig.Group("as"))
+		c.RequireProvide(func() *A { return &A{name: "2"} }, dig.Group("as"), dig.Override())
+
+		type params struct {
+			dig.In
+
+			As []*A `group:"as"`
+		}
+		c.RequireInvoke(func(p params) {
+			assert.Len(t, p.As, 2)
+		})
+	})
+
+	t.Run("dependencies of the new provider", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() *B { return &B{name: "b"} })
+		c.RequireProvide(func() *A { return &A{name: "real"} })
+		c.RequireProvide(func(b *B) *A { return &A{name: b.name} }, dig.Override())
+		c.RequireInvoke(func(a *A) {
+			assert.Equal(t, "b", a.name)
+		})
+	})
+
+	t.Run("already built", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() *A { return &A{name: "real"} })
+		c.RequireInvoke(func(*A) {})
+
+		err := c.Provide(func() *A { return &A{name: "fake"} }, dig.Override())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot override *dig_test.A: it was already built by")
+
+		c.RequireInvoke(func(a *A) {
+			assert.Equal(t, "real", a.name)
+		})
+	})
+
+	t.Run("failed provider can be overridden", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() (*A, error) { return nil, assert.AnError })
+		require.Error(t, c.Invoke(func(*A) {}))
+
+		c.RequireProvide(func() *A { return &A{name: "fake"} }, dig.Override())
+		c.RequireInvoke(func(a *A) {
+			assert.Equal(t, "fake", a.name)
+		})
+	})
+
+	t.Run("transient provider", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() *A { return &A{name: "real"} }, dig.Transient())
+		c.RequireInvoke(func(*A) {})
+		c.RequireProvide(func() *A { return &A{name: "fake"} }, dig.Override())
+		c.RequireInvoke(func(a *A) {
+			assert.Equal(t, "fake", a.name)
+		})
+	})
+
+	t.Run("per-scope provider already built", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() *A { return &A{name: "real"} }, dig.PerScope())
+		c.Scope("child").RequireInvoke(func(*A) {})
+
+		err := c.Provide(func() *A { return &A{name: "fake"} }, dig.Override())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot override *dig_test.A")
+	})
+
+	t.Run("conflicts within the constructor are still reported", func(t *testing.T) {
+		t.Parallel()
+
+		type out struct {
+			dig.Out
+
+			A1 *A
+			A2 *A
+		}
+
+		c := digtest.New(t)
+		err := c.Provide(func() out { return out{} }, dig.Override())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already provided by [0].A1")
+	})
+
+	t.Run("cycle restores the old provider", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func(a *A) *B { return &B{name: a.name} })
+		c.RequireProvide(func() *A { return &A{name: "real"} })
+
+		err := c.Provide(func(b *B) *A { return &A{name: b.name} }, dig.Override())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "this function introduces a cycle")
+
+		c.RequireInvoke(func(b *B) {
+			assert.Equal(t, "real", b.name)
+		})
+	})
+
+	t.Run("replaced constructor is not visualized", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() *A { return &A{name: "real"} })
+		c.RequireProvide(func() *A { return &A{name: "fake"} }, dig.Override())
+
+		var b bytes.Buffer
+		require.NoError(t, dig.Visualize(c.Container, &b))
+		assert.Equal(t, 1, strings.Count(b.String(), "label=<*dig_test.A>"))
+	})
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// ParallelConstruction is an [Option] that allows the container to build
+// independent dependencies concurrently.
+//
+// When a constructor, decorator or invoked function has multiple
+// parameters (including fields of a parameter object), dig builds each
+// of them on a separate goroutine, up to maxWorkers goroutines at a time
+// for the whole container. Parameters that cannot be handed to a worker
+// are built on the calling goroutine. Dependencies shared by several
+// parameters are still constructed only once.
+//
+// If maxWorkers is less than one, runtime.GOMAXPROCS(0) is used.
+//
+// Errors are reported deterministically: when several parameters fail,
+// the error for the first failing parameter in declaration order is
+// returned. Value group providers are called one after another so that
+// value groups behave as they do without this option, and soft value
+// groups are built after all other fields of a parameter object.
+//
+// Panics in constructors are recovered into a [PanicError] if the
+// container was created with [RecoverFromPanics]. Otherwise, they are
+// re-raised on the goroutine that called Invoke.
+//
+// Constructors run with this option must be safe to call concurrently
+// with other constructors.
+func ParallelConstruction(maxWorkers int) Option {
+	return parallelConstructionOption(maxWorkers)
+}
+
+type parallelConstructionOption int
+
+func (o parallelConstructionOption) String() string {
+	return fmt.Sprintf("ParallelConstruction(%d)", int(o))
+}
+
+func (o parallelConstructionOption) applyOption(c *Container) {
+	n := int(o)
+	if n < 1 {
+		n = runtime.GOMAXPROCS(0)
+	}
+	c.scope.workers = newWorkerPool(n)
+}
+
+// workerPool limits the number of goroutines that build dependencies in
+// parallel. It is shared by all Scopes of a Container.
+type workerPool struct {
+	slots chan struct{}
+}
+
+func newWorkerPool(size int) *workerPool {
+	return &workerPool{slots: make(chan struct{}, size)}
+}
+
+// tryAcquire reserves a worker without blocking, reporting whether one was
+// available. Reserved workers must be returned with release.
+func (p *workerPool) tryAcquire() bool {
+	select {
+	case p.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *workerPool) release() {
+	<-p.slots
+}
+
+// buildPanic holds a value recovered from a panic on a worker goroutine so
+// that it can be re-raised on the calling goroutine.
+type buildPanic struct {
+	value interface{}
+}
+
+// buildAll calls build for every index in [0, n), stopping at the first
+// error in index order. If bs collects errors, buildAll calls build for
+// every index instead and returns all the errors.
+//
+// If the container was configured with ParallelConstruction, the calls are
+// spread over the available workers and run concurrently. buildAll then
+// waits for all of them and returns the error of the lowest failing index,
+// so the outcome does not depend on scheduling.
+func buildAll(c containerStore, bs *buildState, n int, build func(i int) error) error {
+	pool := c.workerPool()
+	if pool == nil || n < 2 {
+		var errs []error
+		for i := 0; i < n; i++ {
+			if err := build(i); err != nil {
+				if !bs.collectsErrors() {
+					return err
+				}
+				errs = append(errs, err)
+			}
+		}
+		return newErrDependenciesFailed(errs)
+	}
+
+	var (
+		wg     sync.WaitGroup
+		errs   = make([]error, n)
+		panics = make([]*buildPanic, n)
+	)
+	run := func(i int) {
+		defer func() {
+			if p := recover(); p != nil {
+				panics[i] = &buildPanic{value: p}
+			}
+		}()
+		errs[i] = build(i)
+	}
+
+	for i := 0; i < n; i++ {
+		// Keep the calling goroutine busy with the last item rather than
+		// having it wait idly for a worker.
+		if i == n-1 || !pool.tryAcquire() {
+			run(i)
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer pool.release()
+			run(i)
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < n; i++ {
+		if p := panics[i]; p != nil {
+			panic(p.value)
+		}
+		if err := errs[i]; err != nil && !bs.collectsErrors() {
+			return err
+		}
+	}
+	return newErrDependenciesFailed(errs)
+}
@@ -0,0 +1,224 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/dig"
+	"go.uber.org/dig/internal/digtest"
+)
+
+func TestParallelConstruction(t *testing.T) {
+	t.Parallel()
+
+	type A struct{}
+	type B struct{}
+	type C struct{}
+
+	t.Run("independent constructors run concurrently", func(t *testing.T) {
+		t.Parallel()
+
+		// Each constructor waits for the other to start. This only
+		// completes if both are running at the same time.
+		var started sync.WaitGroup
+		started.Add(2)
+		wait := func() error {
+			done := make(chan struct{})
+			go func() {
+				started.Wait()
+				close(done)
+			}()
+			select {
+			case <-done:
+				return nil
+			case <-time.After(5 * time.Second):
+				return errors.New("constructors did not run concurrently")
+			}
+		}
+
+		c := digtest.New(t, dig.ParallelConstruction(2))
+		c.RequireProvide(func() (*A, error) {
+			started.Done()
+			return &A{}, wait()
+		})
+		c.RequireProvide(func() (*B, error) {
+			started.Done()
+			return &B{}, wait()
+		})
+		c.RequireInvoke(func(*A, *B) {})
+	})
+
+	t.Run("shared dependencies are constructed once", func(t *testing.T) {
+		t.Parallel()
+
+		type params struct {
+			dig.In
+
+			A *A
+			B *B
+			C *C
+		}
+
+		var calls int32
+		c := digtest.New(t, dig.ParallelConstruction(4))
+		c.RequireProvide(func() *A {
+			atomic.AddInt32(&calls, 1)
+			time.Sleep(10 * time.Millisecond)
+			return &A{}
+		})
+		c.RequireProvide(func(*A) *B { return &B{} })
+		c.RequireProvide(func(*A) *C { return &C{} })
+		c.RequireInvoke(func(p params) {
+			assert.NotNil(t, p.A)
+			assert.NotNil(t, p.B)
+			assert.NotNil(t, p.C)
+		})
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("worker limit", func(t *testing.T) {
+		t.Parallel()
+
+		var running, maxRunning int32
+		ctor := func() {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				m := atomic.LoadInt32(&maxRunning)
+				if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+		}
+
+		c := digtest.New(t, dig.ParallelConstruction(1))
+		c.RequireProvide(func() *A { ctor(); return &A{} })
+		c.RequireProvide(func() *B { ctor(); return &B{} })
+		c.RequireProvide(func() *C { ctor(); return &C{} })
+		c.RequireInvoke(func(*A, *B, *C) {})
+
+		// One worker and the goroutine that called Invoke.
+		assert.LessOrEqual(t, atomic.LoadInt32(&maxRunning), int32(2))
+	})
+
+	t.Run("first error is reported", func(t *testing.T) {
+		t.Parallel()
+
+		for i := 0; i < 10; i++ {
+			c := digtest.New(t, dig.ParallelConstruction(4))
+			c.RequireProvide(func() (*A, error) {
+				time.Sleep(time.Millisecond)
+				return nil, errors.New("a failed")
+			})
+			c.RequireProvide(func() (*B, error) {
+				return nil, errors.New("b failed")
+			})
+			c.RequireProvide(func() *C { return &C{} })
+
+			err := c.Invoke(func(*C, *A, *B) {})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "a failed")
+			assert.NotContains(t, err.Error(), "b failed")
+		}
+	})
+
+	t.Run("value groups", func(t *testing.T) {
+		t.Parallel()
+
+		type result struct {
+			dig.Out
+
+			B *B
+			S string `group:"values"`
+		}
+
+		type params struct {
+			dig.In
+
+			Soft   []string `group:"values,soft"`
+			Values []int    `group:"values"`
+			A      *A
+			B      *B
+		}
+
+		c := digtest.New(t, dig.ParallelConstruction(4))
+		c.RequireProvide(func() *A { return &A{} })
+		c.RequireProvide(func() int { return 1 }, dig.Group("values"))
+		c.RequireProvide(func() int { return 2 }, dig.Group("values"))
+		c.RequireProvide(func() result { return result{B: &B{}, S: "b"} })
+		c.RequireProvide(func() string { return "c" }, dig.Group("values"))
+
+		c.RequireInvoke(func(p params) {
+			assert.ElementsMatch(t, []int{1, 2}, p.Values)
+			// Soft groups are built after the other fields, so the
+			// value provided alongside *B is available.
+			assert.Equal(t, []string{"b"}, p.Soft)
+		})
+	})
+
+	t.Run("panic is propagated to the caller", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t, dig.ParallelConstruction(4))
+		c.RequireProvide(func() *A { return &A{} })
+		c.RequireProvide(func() *B { panic("great sadness") })
+
+		assert.PanicsWithValue(t, "great sadness", func() {
+			_ = c.Invoke(func(*B, *A) {})
+		})
+
+		// The panicking constructor may be called again.
+		assert.Panics(t, func() {
+			_ = c.Invoke(func(*B) {})
+		})
+	})
+
+	t.Run("RecoverFromPanics", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t, dig.ParallelConstruction(4), dig.RecoverFromPanics())
+		c.RequireProvide(func() *A { return &A{} })
+		c.RequireProvide(func() *B { panic("great sadness") })
+
+		err := c.Invoke(func(*B, *A) {})
+		var pe dig.PanicError
+		require.True(t, errors.As(err, &pe), "expected a PanicError")
+		assert.Equal(t, "great sadness", pe.Panic)
+	})
+
+	t.Run("scopes share workers", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t, dig.ParallelConstruction(4))
+		c.RequireProvide(func() *A { return &A{} })
+		s := c.Scope("child")
+		s.RequireProvide(func() *B { return &B{} })
+		s.RequireInvoke(func(*A, *B) {})
+	})
+}
@@ -43,14 +43,16 @@ import (
 //	              A slice consuming a value group. This will receive all
 //	              values produced with a `group:".."` tag with the same name
 //	              as a slice.
+//	paramLazy     A dig.Lazy that builds another param on demand.
 type param interface {
 	fmt.Stringer
 
 	// Build this dependency and any of its dependencies from the provided
-	// Container.
+	// Container. bs records the constructors and decorators that are
+	// already running on this call path.
 	//
 	// This MAY panic if the param does not produce a single value.
-	Build(store containerStore) (reflect.Value, error)
+	Build(store containerStore, bs *buildState) (reflect.Value, error)
 
 	// DotParam returns a slice of dot.Param(s).
 	DotParam() []*dot.Param
@@ -78,6 +80,8 @@ func newParam(t reflect.Type, c containerStore) (param, error) {
 	case t.Kind() == reflect.Ptr && IsIn(t.Elem()):
 		return nil, newErrInvalidInput(fmt.Sprintf(
 			"cannot depend on a pointer to a parameter object, use a value instead: %v is a pointer to a struct that embeds dig.In", t), nil)
+	case isLazy(t):
+		return newParamLazy(t, c)
 	default:
 		return paramSingle{Type: t}, nil
 	}
@@ -137,21 +141,21 @@ func newParamList(ctype reflect.Type, c containerStore) (paramList, error) {
 	return pl, nil
 }
 
-func (pl paramList) Build(containerStore) (reflect.Value, error) {
+func (pl paramList) Build(containerStore, *buildState) (reflect.Value, error) {
 	digerror.BugPanicf("paramList.Build() must never be called")
 	panic("") // Unreachable, as BugPanicf above will panic.
 }
 
 // BuildList returns an ordered list of values which may be passed directly
 // to the underlying constructor.
-func (pl paramList) BuildList(c containerStore) ([]reflect.Value, error) {
+func (pl paramList) BuildList(c containerStore, bs *buildState) ([]reflect.Value, error) {
 	args := make([]reflect.Value, len(pl.Params))
-	for i, p := range pl.Params {
-		var err error
-		args[i], err = p.Build(c)
-		if err != nil {
-			return nil, err
-		}
+	err := buildAll(c, bs, len(pl.Params), func(i int) (err error) {
+		args[i], err = pl.Params[i].Build(c, bs)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 	return args, nil
 }
@@ -178,6 +182,12 @@ func (ps paramSingle) DotParam() []*dot.Param {
 	}
 }
 
+// isContext reports whether the parameter can be satisfied with the
+// context of the Invoke.
+func (ps paramSingle) isContext() bool {
+	return ps.Type == _contextType && ps.Name == ""
+}
+
 func (ps paramSingle) String() string {
 	// tally.Scope[optional] means optional
 	// tally.Scope[optional, name="foo"] means named optional
@@ -211,7 +221,7 @@ func (ps paramSingle) getDecoratedValue(c containerStore) (reflect.Value, bool)
 // current scope, if there are any. If there are multiple Scopes that decorates
 // this parameter, the closest one to the Scope that invoked this will be used.
 // If there are no decorators associated with this parameter, _noValue is returned.
-func (ps paramSingle) buildWithDecorators(c containerStore) (v reflect.Value, found bool, err error) {
+func (ps paramSingle) buildWithDecorators(c containerStore, bs *buildState) (v reflect.Value, found bool, err error) {
 	var (
 		d               decorator
 		decoratingScope containerStore
@@ -222,7 +232,7 @@ func (ps paramSingle) buildWithDecorators(c containerStore) (v reflect.Value, fo
 		if d, found = s.getValueDecorator(ps.Name, ps.Type); !found {
 			continue
 		}
-		if d.State() == decoratorOnStack {
+		if bs.has(d) {
 			// This decorator is already being run.
 			// Avoid a cycle and look further.
 			d = nil
@@ -234,7 +244,7 @@ func (ps paramSingle) buildWithDecorators(c containerStore) (v reflect.Value, fo
 	if !found || d == nil {
 		return _noValue, false, nil
 	}
-	if err = d.Call(decoratingScope); err != nil {
+	if err = d.Call(decoratingScope, bs); err != nil {
 		v, err = _noValue, errParamSingleFailed{
 			CtorID: 1,
 			Key:    key{t: ps.Type, name: ps.Name},
@@ -246,8 +256,9 @@ func (ps paramSingle) buildWithDecorators(c containerStore) (v reflect.Value, fo
 	return
 }
 
-func (ps paramSingle) Build(c containerStore) (reflect.Value, error) {
-	v, found, err := ps.buildWithDecorators(c)
+func (ps paramSingle) Build(c containerStore, bs *buildState) (reflect.Value, error) {
+	bs = bs.requesting(Input{t: ps.Type, optional: ps.Optional, name: ps.Name})
+	v, found, err := ps.buildWithDecorators(c, bs)
 	if found {
 		return v, err
 	}
@@ -265,11 +276,18 @@ func (ps paramSingle) Build(c containerStore) (reflect.Value, error) {
 	// rather than starting at base.
 	var providers []provider
 	var providingContainer containerStore
-	for _, container := range c.storesToRoot() {
+	for i, container := range c.storesToRoot() {
 		// first check if the scope already has cached a value for the type.
 		if v, ok := container.getValue(ps.Name, ps.Type); ok {
 			return v, nil
 		}
+		// Values built by per-scope constructors are only visible to the
+		// scope they were built for.
+		if i == 0 {
+			if v, ok := container.getScopedValue(ps.Name, ps.Type); ok {
+				return v, nil
+			}
+		}
 		providers = container.getValueProviders(ps.Name, ps.Type)
 		if len(providers) > 0 {
 			providingContainer = container
@@ -278,14 +296,35 @@ func (ps paramSingle) Build(c containerStore) (reflect.Value, error) {
 	}
 
 	if len(providers) == 0 {
+		if ps.isContext() {
+			ctx := bs.context()
+			return reflect.ValueOf(&ctx).Elem(), nil
+		}
 		if ps.Optional {
 			return reflect.Zero(ps.Type), nil
 		}
 		return _noValue, newErrMissingTypes(c, key{name: ps.Name, t: ps.Type})
 	}
 
+	// Values of transient providers aren't stored in the container, so we
+	// have to hold on to them.
+	var (
+		produced *stagingContainerWriter
+		scoped   bool
+	)
 	for _, n := range providers {
-		err := n.Call(n.OrigScope())
+		var err error
+		switch {
+		case n.Transient():
+			produced, err = n.Produce(n.OrigScope(), bs)
+		case n.PerScope():
+			// Build the value for the requesting scope, from the
+			// requesting scope.
+			scoped = true
+			err = n.Call(c, bs)
+		default:
+			err = n.Call(n.OrigScope(), bs)
+		}
 		if err == nil {
 			continue
 		}
@@ -303,6 +342,14 @@ func (ps paramSingle) Build(c containerStore) (reflect.Value, error) {
 		}
 	}
 
+	if produced != nil {
+		return produced.values[key{name: ps.Name, t: ps.Type}], nil
+	}
+	if scoped {
+		v, _ = c.getScopedValue(ps.Name, ps.Type)
+		return v, nil
+	}
+
 	// If we get here, it's impossible for the value to be absent from the
 	// container.
 	v, _ = providingContainer.getValue(ps.Name, ps.Type)
@@ -339,18 +386,21 @@ func getParamOrder(gh *graphHolder, param param) []int {
 	var orders []int
 	switch p := param.(type) {
 	case paramSingle:
-		providers := gh.s.getAllValueProviders(p.Name, p.Type)
+		providers := gh.s.getAllProviders(key{name: p.Name, t: p.Type})
 		for _, provider := range providers {
 			orders = append(orders, provider.Order(gh.s))
 		}
 	case paramGroupedSlice:
 		// value group parameters have nodes of their own.
 		// We can directly return that here.
-		orders = append(orders, p.orders[gh.s])
+		orders = append(orders, p.order(gh.s))
 	case paramObject:
 		for _, pf := range p.Fields {
 			orders = append(orders, getParamOrder(gh, pf.Param)...)
 		}
+	case paramLazy:
+		// Lazy values are built after the function that depends on
+		// them, so they don't count towards cycles.
 	}
 	return orders
 }
@@ -394,7 +444,7 @@ func newParamObject(t reflect.Type, c containerStore) (paramObject, error) {
 	return po, nil
 }
 
-func (po paramObject) Build(c containerStore) (reflect.Value, error) {
+func (po paramObject) Build(c containerStore, bs *buildState) (reflect.Value, error) {
 	dest := reflect.New(po.Type).Elem()
 	// We have to build soft groups after all other fields, to avoid cases
 	// when a field calls a provider for a soft value group, but the value is
@@ -408,13 +458,18 @@ func (po paramObject) Build(c containerStore) (reflect.Value, error) {
 		}
 		fields = append(fields, f)
 	}
-	fields = append(fields, softGroupsQueue...)
-	for _, f := range fields {
-		v, err := f.Build(c)
+	for _, fields := range [][]paramObjectField{fields, softGroupsQueue} {
+		values := make([]reflect.Value, len(fields))
+		err := buildAll(c, bs, len(fields), func(i int) (err error) {
+			values[i], err = fields[i].Build(c, bs)
+			return err
+		})
 		if err != nil {
 			return dest, err
 		}
-		dest.Field(f.FieldIndex).Set(v)
+		for i, f := range fields {
+			dest.Field(f.FieldIndex).Set(values[i])
+		}
 	}
 	return dest, nil
 }
@@ -465,6 +520,12 @@ func newParamObjectField(idx int, f reflect.StructField, c containerStore) (para
 		}
 	}
 
+	// Tags of a dig.Lazy apply to the value it builds.
+	pl, isLazy := p.(paramLazy)
+	if isLazy {
+		p = pl.Value
+	}
+
 	if ps, ok := p.(paramSingle); ok {
 		ps.Name = f.Tag.Get(_nameTag)
 
@@ -477,12 +538,17 @@ func newParamObjectField(idx int, f reflect.StructField, c containerStore) (para
 		p = ps
 	}
 
+	if isLazy {
+		pl.Value = p
+		p = pl
+	}
+
 	pof.Param = p
 	return pof, nil
 }
 
-func (pof paramObjectField) Build(c containerStore) (reflect.Value, error) {
-	v, err := pof.Param.Build(c)
+func (pof paramObjectField) Build(c containerStore, bs *buildState) (reflect.Value, error) {
+	v, err := pof.Param.Build(c, bs)
 	if err != nil {
 		return v, err
 	}
@@ -506,6 +572,18 @@ type paramGroupedSlice struct {
 	orders map[*Scope]int
 }
 
+// order returns the order of this value group's node in the graph of s.
+// Scopes that were cloned share the node with the Scope they were cloned
+// from.
+func (pt paramGroupedSlice) order(s *Scope) int {
+	for ; s != nil; s = s.clonedFrom {
+		if o, ok := pt.orders[s]; ok {
+			return o
+		}
+	}
+	return 0
+}
+
 func (pt paramGroupedSlice) String() string {
 	// io.Reader[group="foo"] refers to a group of io.Readers called 'foo'
 	return fmt.Sprintf("%v[group=%q]", pt.Type.Elem(), pt.Group)
@@ -518,6 +596,7 @@ func (pt paramGroupedSlice) DotParam() []*dot.Param {
 				Type:  pt.Type,
 				Group: pt.Group,
 			},
+			Soft: pt.Soft,
 		},
 	}
 }
@@ -576,17 +655,17 @@ func (pt paramGroupedSlice) getDecoratedValues(c containerStore) (reflect.Value,
 // The order in which the decorators are invoked is from the top level scope to
 // the current scope, to account for decorators that decorate values that were
 // already decorated.
-func (pt paramGroupedSlice) callGroupDecorators(c containerStore) error {
+func (pt paramGroupedSlice) callGroupDecorators(c containerStore, bs *buildState) error {
 	stores := c.storesToRoot()
 	for i := len(stores) - 1; i >= 0; i-- {
 		c := stores[i]
 		if d, found := c.getGroupDecorator(pt.Group, pt.Type.Elem()); found {
-			if d.State() == decoratorOnStack {
+			if bs.has(d) {
 				// This decorator is already being run. Avoid cycle
 				// and look further.
 				continue
 			}
-			if err := d.Call(c); err != nil {
+			if err := d.Call(c, bs); err != nil {
 				return errParamGroupFailed{
 					CtorID: d.ID(),
 					Key:    key{group: pt.Group, t: pt.Type.Elem()},
@@ -600,30 +679,56 @@ func (pt paramGroupedSlice) callGroupDecorators(c containerStore) error {
 
 // search the given container and its parent for matching group providers and
 // call them to commit values. If an error is encountered, return the number
-// of providers called and a non-nil error from the first provided.
-func (pt paramGroupedSlice) callGroupProviders(c containerStore) (int, error) {
-	itemCount := 0
+// of providers called and a non-nil error from the first provided, or from
+// all of them if the build collects errors.
+//
+// Values produced by transient providers are not committed. They are
+// returned instead.
+func (pt paramGroupedSlice) callGroupProviders(c containerStore, bs *buildState) (int, []reflect.Value, error) {
+	var (
+		itemCount int
+		produced  []reflect.Value
+		errs      []error
+	)
+	k := key{group: pt.Group, t: pt.Type.Elem()}
 	for _, c := range c.storesToRoot() {
 		providers := c.getGroupProviders(pt.Group, pt.Type.Elem())
 		itemCount += len(providers)
 		for _, n := range providers {
-			if err := n.Call(n.OrigScope()); err != nil {
-				return 0, errParamGroupFailed{
+			var err error
+			if n.Transient() {
+				var sr *stagingContainerWriter
+				if sr, err = n.Produce(n.OrigScope(), bs); err == nil {
+					produced = append(produced, sr.groups[k]...)
+				}
+			} else {
+				err = n.Call(n.OrigScope(), bs)
+			}
+			if err != nil {
+				err = errParamGroupFailed{
 					CtorID: n.ID(),
-					Key:    key{group: pt.Group, t: pt.Type.Elem()},
+					Key:    k,
 					Reason: err,
 				}
+				if !bs.collectsErrors() {
+					return 0, nil, err
+				}
+				errs = append(errs, err)
 			}
 		}
 	}
-	return itemCount, nil
+	if err := newErrDependenciesFailed(errs); err != nil {
+		return 0, nil, err
+	}
+	return itemCount, produced, nil
 }
 
-func (pt paramGroupedSlice) Build(c containerStore) (reflect.Value, error) {
+func (pt paramGroupedSlice) Build(c containerStore, bs *buildState) (reflect.Value, error) {
+	bs = bs.requesting(Input{t: pt.Type, group: pt.Group})
 	// do not call this if we are already inside a decorator since
 	// it will result in an infinite recursion. (i.e. decorate -> params.BuildList() -> Decorate -> params.BuildList...)
 	// this is safe since a value can be decorated at most once in a given scope.
-	if err := pt.callGroupDecorators(c); err != nil {
+	if err := pt.callGroupDecorators(c, bs); err != nil {
 		return _noValue, err
 	}
 
@@ -634,10 +739,13 @@ func (pt paramGroupedSlice) Build(c containerStore) (reflect.Value, error) {
 
 	// If we do not have any decorated values and the group isn't soft,
 	// find the providers and call them.
-	itemCount := 0
+	var (
+		itemCount int
+		produced  []reflect.Value
+	)
 	if !pt.Soft {
 		var err error
-		itemCount, err = pt.callGroupProviders(c)
+		itemCount, produced, err = pt.callGroupProviders(c, bs)
 		if err != nil {
 			return _noValue, err
 		}
@@ -648,6 +756,7 @@ func (pt paramGroupedSlice) Build(c containerStore) (reflect.Value, error) {
 	for _, c := range stores {
 		result = reflect.Append(result, c.getValueGroup(pt.Group, pt.Type.Elem())...)
 	}
+	result = reflect.Append(result, produced...)
 	return result, nil
 }
 
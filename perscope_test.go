@@ -0,0 +1,245 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/dig"
+	"go.uber.org/dig/internal/digtest"
+)
+
+func TestPerScope(t *testing.T) {
+	t.Parallel()
+
+	type Request struct{ id string }
+	type Session struct{ req *Request }
+
+	t.Run("one value per scope", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int
+		c := digtest.New(t)
+		c.RequireProvide(func() *Session {
+			calls++
+			return &Session{}
+		}, dig.PerScope())
+
+		s1 := c.Scope("s1")
+		s2 := c.Scope("s2")
+		gc := s1.Scope("grandchild")
+
+		type invoker interface {
+			Invoke(interface{}, ...dig.InvokeOption) error
+		}
+		get := func(s invoker) *Session {
+			var sess *Session
+			require.NoError(t, s.Invoke(func(s *Session) { sess = s }))
+			return sess
+		}
+
+		first := get(s1)
+		assert.Same(t, first, get(s1))
+		assert.NotSame(t, first, get(s2))
+		assert.NotSame(t, first, get(gc))
+		assert.NotSame(t, first, get(c))
+		assert.Equal(t, 4, calls)
+	})
+
+	t.Run("dependencies are resolved from the scope", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func(r *Request) *Session {
+			return &Session{req: r}
+		}, dig.PerScope())
+
+		for _, id := range []string{"a", "b"} {
+			s := c.Scope(id)
+			s.RequireProvide(func() *Request { return &Request{id: id} })
+			s.RequireInvoke(func(sess *Session) {
+				assert.Equal(t, id, sess.req.id)
+			})
+		}
+
+		err := c.Invoke(func(*Session) {})
+		require.Error(t, err, "root scope has no *Request")
+		assert.Contains(t, err.Error(), "missing type: *dig_test.Request")
+	})
+
+	t.Run("singletons see the value of their scope", func(t *testing.T) {
+		t.Parallel()
+
+		type Service struct{ sess *Session }
+
+		c := digtest.New(t)
+		c.RequireProvide(func() *Session { return &Session{} }, dig.PerScope())
+		c.RequireProvide(func(s *Session) *Service { return &Service{sess: s} })
+
+		var rootSession *Session
+		c.RequireInvoke(func(s *Session) { rootSession = s })
+
+		child := c.Scope("child")
+		child.RequireInvoke(func(svc *Service, s *Session) {
+			assert.Same(t, rootSession, svc.sess)
+			assert.NotSame(t, rootSession, s)
+		})
+	})
+
+	t.Run("provided to a child scope", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		s := c.Scope("child")
+		s.RequireProvide(func() *Session { return &Session{} }, dig.PerScope())
+
+		var child *Session
+		s.RequireInvoke(func(sess *Session) { child = sess })
+		s.Scope("grandchild").RequireInvoke(func(sess *Session) {
+			assert.NotSame(t, child, sess)
+		})
+	})
+
+	t.Run("cleanups run with their scope", func(t *testing.T) {
+		t.Parallel()
+
+		var closed []string
+		c := digtest.New(t)
+		c.RequireProvide(func(r *Request) (*Session, func()) {
+			return &Session{req: r}, func() { closed = append(closed, r.id) }
+		}, dig.PerScope(), dig.Cleanup())
+
+		s1 := c.Scope("s1")
+		s1.RequireProvide(func() *Request { return &Request{id: "s1"} })
+		s1.RequireInvoke(func(*Session) {})
+
+		s2 := c.Scope("s2")
+		s2.RequireProvide(func() *Request { return &Request{id: "s2"} })
+		s2.RequireInvoke(func(*Session) {})
+
+		require.NoError(t, s1.Close())
+		assert.Equal(t, []string{"s1"}, closed)
+
+		require.NoError(t, c.Close())
+		assert.Equal(t, []string{"s1", "s2"}, closed)
+	})
+
+	t.Run("concurrent requests in a scope", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int32
+		c := digtest.New(t)
+		c.RequireProvide(func() *Session {
+			atomic.AddInt32(&calls, 1)
+			return &Session{}
+		}, dig.PerScope())
+
+		s := c.Scope("child")
+		var (
+			wg       sync.WaitGroup
+			sessions [10]*Session
+		)
+		for i := range sessions {
+			i := i
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.RequireInvoke(func(sess *Session) { sessions[i] = sess })
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+		for _, sess := range sessions {
+			assert.Same(t, sessions[0], sess)
+		}
+	})
+
+	t.Run("invalid uses", func(t *testing.T) {
+		t.Parallel()
+
+		type result struct {
+			dig.Out
+
+			Session *Session
+			Value   int `group:"values"`
+		}
+
+		tests := []struct {
+			desc    string
+			provide func(*digtest.Container) error
+			wantErr string
+		}{
+			{
+				desc: "value group",
+				provide: func(c *digtest.Container) error {
+					return c.Provide(func() int { return 0 }, dig.Group("values"), dig.PerScope())
+				},
+				wantErr: `cannot use dig.PerScope() with value groups: group:"values" provided`,
+			},
+			{
+				desc: "value group in result object",
+				provide: func(c *digtest.Container) error {
+					return c.Provide(func() result { return result{} }, dig.PerScope())
+				},
+				wantErr: `cannot provide int[group="values"] per scope`,
+			},
+			{
+				desc: "transient",
+				provide: func(c *digtest.Container) error {
+					return c.Provide(func() *Session { return nil }, dig.PerScope(), dig.Transient())
+				},
+				wantErr: "cannot use dig.Transient() with dig.PerScope()",
+			},
+			{
+				desc: "decorated",
+				provide: func(c *digtest.Container) error {
+					c.RequireDecorate(func(s *Session) *Session { return s })
+					return c.Provide(func() *Session { return nil }, dig.PerScope())
+				},
+				wantErr: "cannot provide *dig_test.Session per scope: it is already decorated",
+			},
+			{
+				desc: "decorate",
+				provide: func(c *digtest.Container) error {
+					c.RequireProvide(func() *Session { return nil }, dig.PerScope())
+					return c.Decorate(func(s *Session) *Session { return s })
+				},
+				wantErr: "*dig_test.Session is provided by a per-scope constructor",
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.desc, func(t *testing.T) {
+				t.Parallel()
+
+				err := tt.provide(digtest.New(t))
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			})
+		}
+	})
+}
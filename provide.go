@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	"go.uber.org/dig/internal/digreflect"
 	"go.uber.org/dig/internal/dot"
@@ -45,6 +46,12 @@ type provideOptions struct {
 	Exported       bool
 	Callback       Callback
 	BeforeCallback BeforeCallback
+	Transient      bool
+	PerScope       bool
+	Override       bool
+	Timeout        time.Duration
+	Retry          *RetryPolicy
+	Cleanup        bool
 }
 
 func (o *provideOptions) Validate() error {
@@ -53,6 +60,25 @@ func (o *provideOptions) Validate() error {
 			return newErrInvalidInput(
 				fmt.Sprintf("cannot use named values with value groups: name:%q provided with group:%q", o.Name, o.Group), nil)
 		}
+		if o.PerScope {
+			return newErrInvalidInput(
+				fmt.Sprintf("cannot use dig.PerScope() with value groups: group:%q provided", o.Group), nil)
+		}
+	}
+
+	if o.Transient && o.PerScope {
+		return newErrInvalidInput("cannot use dig.Transient() with dig.PerScope()", nil)
+	}
+
+	if o.Timeout < 0 {
+		return newErrInvalidInput(
+			fmt.Sprintf("invalid dig.Timeout(%v): timeout must not be negative", o.Timeout), nil)
+	}
+
+	if o.Retry != nil {
+		if err := o.Retry.validate(); err != nil {
+			return err
+		}
 	}
 
 	// Names must be representable inside a backquoted string. The only
@@ -151,21 +177,63 @@ type ProvideInfo struct {
 	ID      ID
 	Inputs  []*Input
 	Outputs []*Output
+
+	location Location
 }
 
+// Location returns where the constructor was defined.
+func (i *ProvideInfo) Location() Location { return i.location }
+
 // Input contains information on an input parameter of a function.
 type Input struct {
-	t           reflect.Type
-	optional    bool
-	name, group string
+	t              reflect.Type
+	optional, lazy bool
+	name, group    string
 }
 
+func newInputs(pl paramList) []*Input {
+	params := pl.DotParam()
+	inputs := make([]*Input, len(params))
+	for i, param := range params {
+		inputs[i] = &Input{
+			t:        param.Type,
+			optional: param.Optional,
+			lazy:     param.Lazy,
+			name:     param.Name,
+			group:    param.Group,
+		}
+	}
+	return inputs
+}
+
+// Type returns the type of the parameter. For value groups, this is the
+// slice type the group is consumed as.
+func (i *Input) Type() reflect.Type { return i.t }
+
+// Name returns the name of the value requested by the parameter, if any.
+func (i *Input) Name() string { return i.name }
+
+// Group returns the name of the value group requested by the parameter,
+// if any.
+func (i *Input) Group() string { return i.group }
+
+// Optional reports whether the parameter may be left unset if the
+// container cannot build it.
+func (i *Input) Optional() bool { return i.optional }
+
+// Lazy reports whether the parameter is a dig.Lazy, which builds the value
+// only when it's used. Type then reports the type of that value.
+func (i *Input) Lazy() bool { return i.lazy }
+
 func (i *Input) String() string {
-	toks := make([]string, 0, 3)
+	toks := make([]string, 0, 4)
 	t := i.t.String()
 	if i.optional {
 		toks = append(toks, "optional")
 	}
+	if i.lazy {
+		toks = append(toks, "lazy")
+	}
 	if i.name != "" {
 		toks = append(toks, fmt.Sprintf("name = %q", i.name))
 	}
@@ -185,6 +253,29 @@ type Output struct {
 	name, group string
 }
 
+func newOutputs(rl resultList) []*Output {
+	results := rl.DotResult()
+	outputs := make([]*Output, len(results))
+	for i, res := range results {
+		outputs[i] = &Output{
+			t:     res.Type,
+			name:  res.Name,
+			group: res.Group,
+		}
+	}
+	return outputs
+}
+
+// Type returns the type of the produced value. Values produced with
+// dig.As are reported as separate outputs.
+func (o *Output) Type() reflect.Type { return o.t }
+
+// Name returns the name of the produced value, if any.
+func (o *Output) Name() string { return o.name }
+
+// Group returns the name of the value group the value is added to, if any.
+func (o *Output) Group() string { return o.group }
+
 func (o *Output) String() string {
 	toks := make([]string, 0, 2)
 	t := o.t.String()
@@ -334,6 +425,166 @@ func (o provideExportOption) applyProvideOption(opts *provideOptions) {
 	opts.Exported = o.exported
 }
 
+// Transient is a ProvideOption that specifies that the constructor must be
+// called for every dependent of the values it produces, instead of once.
+// The produced values are never stored in the container.
+//
+// For example, the following gives every consumer of *bytes.Buffer its own
+// buffer.
+//
+//	c.Provide(func() *bytes.Buffer { return new(bytes.Buffer) }, dig.Transient())
+//
+// Each parameter that depends on a transient constructor triggers a
+// separate call, even if several of them are requested by the same
+// function. A transient constructor that adds values to a value group is
+// called each time the group is requested, but its values never show up
+// in soft value groups.
+//
+// Values produced by transient constructors cannot be decorated.
+func Transient() ProvideOption {
+	return provideTransientOption{}
+}
+
+type provideTransientOption struct{}
+
+func (provideTransientOption) String() string {
+	return "Transient()"
+}
+
+func (provideTransientOption) applyProvideOption(opts *provideOptions) {
+	opts.Transient = true
+}
+
+// PerScope is a ProvideOption that specifies that the constructor must be
+// called once for every Scope that requests its values, instead of once for
+// the Scope it was provided to. Each Scope caches its own values, and the
+// dependencies of the constructor are resolved from that Scope.
+//
+// For example, the following builds a new *RequestContext for every child
+// Scope, using the *http.Request provided to that Scope.
+//
+//	c.Provide(func(r *http.Request) *RequestContext {
+//	  return newRequestContext(r)
+//	}, dig.PerScope())
+//
+//	s := c.Scope("request")
+//	s.Provide(func() *http.Request { return req })
+//	s.Invoke(func(rc *RequestContext) { ... })
+//
+// Constructors called by a Scope's ancestors on their own behalf see the
+// value built for that ancestor. Cleanup functions returned by per-scope
+// constructors are run when the Scope the value was built for is closed.
+//
+// Per-scope constructors cannot provide values to value groups, and their
+// values cannot be decorated.
+func PerScope() ProvideOption {
+	return providePerScopeOption{}
+}
+
+type providePerScopeOption struct{}
+
+func (providePerScopeOption) String() string {
+	return "PerScope()"
+}
+
+func (providePerScopeOption) applyProvideOption(opts *provideOptions) {
+	opts.PerScope = true
+}
+
+// Override is a ProvideOption that specifies that the constructor replaces
+// the constructors that already provide its results to the Scope, instead
+// of conflicting with them. This is useful to swap out a dependency for a
+// fake in tests, or to adjust the wiring of an application per environment.
+//
+//	c.Provide(NewClient)
+//	c.Provide(NewFakeClient, dig.Override())
+//
+// Only the constructors provided to the same Scope are replaced. A
+// constructor that produces other values besides the overridden ones keeps
+// providing them. Values added to value groups are never replaced.
+//
+// Providers cannot be overridden once they have been called: Provide fails
+// if a value of an overridden type was already built.
+func Override() ProvideOption {
+	return provideOverrideOption{}
+}
+
+type provideOverrideOption struct{}
+
+func (provideOverrideOption) String() string {
+	return "Override()"
+}
+
+func (provideOverrideOption) applyProvideOption(opts *provideOptions) {
+	opts.Override = true
+}
+
+// Timeout is a ProvideOption that bounds the time Invoke waits for the
+// constructor to return once its dependencies are built.
+//
+// If the constructor hasn't returned by then, Invoke stops waiting for it
+// and fails with a [CanceledError]. The constructor keeps running in the
+// background and the values it eventually returns are discarded. Their
+// cleanup functions, if any, run when the container is closed.
+//
+// The timeout only applies to waiting: the constructor and its
+// dependencies are given the context of the Invoke as usual, since the
+// values they build may outlive the call. Constructors that need their
+// own deadline should derive it from that context.
+//
+//	c.Provide(func(ctx context.Context, cfg *Config) (*sql.DB, error) {
+//	  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+//	  defer cancel()
+//	  return dial(ctx, cfg.DSN)
+//	}, dig.Timeout(10*time.Second))
+//
+// For constructors provided with [Retry] as well, the timeout bounds all
+// the attempts together, including the waits between them.
+//
+// A zero duration means no timeout.
+func Timeout(d time.Duration) ProvideOption {
+	return provideTimeoutOption(d)
+}
+
+type provideTimeoutOption time.Duration
+
+func (o provideTimeoutOption) String() string {
+	return fmt.Sprintf("Timeout(%v)", time.Duration(o))
+}
+
+func (o provideTimeoutOption) applyProvideOption(opts *provideOptions) {
+	opts.Timeout = time.Duration(o)
+}
+
+// Cleanup is a ProvideOption that specifies that the constructor returns a
+// cleanup function after its other results, and before the error if it
+// returns one. Cleanup functions have the type func() or func() error and
+// are not provided to the container.
+//
+//	c.Provide(func(cfg *Config) (*Conn, func() error, error) {
+//	  conn, err := dial(cfg)
+//	  if err != nil {
+//	    return nil, nil, err
+//	  }
+//	  return conn, conn.Close, nil
+//	}, dig.Cleanup())
+//
+// Provide fails if the constructor doesn't return a cleanup function. See
+// [Scope.Close] for when cleanup functions are run.
+func Cleanup() ProvideOption {
+	return provideCleanupOption{}
+}
+
+type provideCleanupOption struct{}
+
+func (provideCleanupOption) String() string {
+	return "Cleanup()"
+}
+
+func (provideCleanupOption) applyProvideOption(opts *provideOptions) {
+	opts.Cleanup = true
+}
+
 // provider encapsulates a user-provided constructor.
 type provider interface {
 	// ID is a unique numerical identifier for this provider.
@@ -359,7 +610,20 @@ type provider interface {
 	//
 	// The values produced by this provider should be submitted into the
 	// containerStore.
-	Call(containerStore) error
+	Call(containerStore, *buildState) error
+
+	// Transient reports whether this provider must produce new values for
+	// every dependent.
+	Transient() bool
+
+	// PerScope reports whether this provider must produce values for every
+	// Scope that requests them. Such values are committed to the Scope
+	// the provider is called with rather than the one it belongs to.
+	PerScope() bool
+
+	// Calls the underlying constructor like Call, but returns the values
+	// it produced instead of submitting them into the containerStore.
+	Produce(containerStore, *buildState) (*stagingContainerWriter, error)
 
 	CType() reflect.Type
 
@@ -376,7 +640,7 @@ type provider interface {
 // This function will be called AT MOST ONCE when a type produced by it, or a
 // type that consumes this function's output, is requested via Invoke. If the
 // same types are requested multiple times, the previously produced value will
-// be reused.
+// be reused. Use the Transient option to get a new value every time instead.
 //
 // Provide accepts argument types or dig.In structs as dependencies, and
 // separate return values or dig.Out structs for results.
@@ -384,6 +648,13 @@ func (c *Container) Provide(constructor interface{}, opts ...ProvideOption) erro
 	return c.scope.Provide(constructor, opts...)
 }
 
+// Replace teaches the container how to build values of one or more types,
+// replacing the constructors that provided them before. It is equivalent
+// to calling Provide with the Override option.
+func (c *Container) Replace(constructor interface{}, opts ...ProvideOption) error {
+	return c.scope.Replace(constructor, opts...)
+}
+
 // Provide teaches the Scope how to build values of one or more types and
 // expresses their dependencies.
 //
@@ -421,6 +692,11 @@ func (s *Scope) Provide(constructor interface{}, opts ...ProvideOption) error {
 		return err
 	}
 
+	// The outputs of the constructor are reported to event handlers.
+	if s.emitting() && options.Info == nil {
+		options.Info = new(ProvideInfo)
+	}
+
 	if err := s.provide(constructor, options); err != nil {
 		var errFunc *digreflect.Func
 		if options.Location == nil {
@@ -429,15 +705,37 @@ func (s *Scope) Provide(constructor interface{}, opts ...ProvideOption) error {
 			errFunc = options.Location
 		}
 
-		return errProvide{
+		err = errProvide{
 			Func:   errFunc,
 			Reason: err,
 		}
+		if s.emitting() {
+			s.emit(ProvideFailed{Location: newLocation(errFunc), Scope: s.name, Err: err})
+		}
+		return err
+	}
+
+	if s.emitting() {
+		loc := options.Location
+		if loc == nil {
+			loc = digreflect.InspectFunc(constructor)
+		}
+		s.emit(Provided{Location: newLocation(loc), Scope: s.name, Outputs: options.Info.Outputs})
 	}
 	return nil
 }
 
+// Replace teaches the Scope how to build values of one or more types,
+// replacing the constructors that provided them to this Scope before. It is
+// equivalent to calling Provide with the Override option.
+func (s *Scope) Replace(constructor interface{}, opts ...ProvideOption) error {
+	return s.Provide(constructor, append(opts, Override())...)
+}
+
 func (s *Scope) provide(ctor interface{}, opts provideOptions) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	// If Export option is provided to the constructor, this should be injected to the
 	// root-level Scope (Container) to allow it to propagate to all other Scopes.
 	origScope := s
@@ -474,36 +772,75 @@ func (s *Scope) provide(ctor interface{}, opts provideOptions) (err error) {
 			Location:       opts.Location,
 			Callback:       opts.Callback,
 			BeforeCallback: opts.BeforeCallback,
+			Exported:       opts.Exported,
+			Transient:      opts.Transient,
+			PerScope:       opts.PerScope,
+			Timeout:        opts.Timeout,
+			Retry:          opts.Retry,
+			Cleanup:        opts.Cleanup,
 		},
 	)
 	if err != nil {
 		return err
 	}
 
-	keys, err := s.findAndValidateResults(n.ResultList())
+	keys, err := s.findAndValidateResults(n.ResultList(), opts.Override)
 	if err != nil {
 		return err
 	}
 
+	if n.transient {
+		for k := range keys {
+			if s.isDecorated(k) {
+				return newErrInvalidInput(
+					fmt.Sprintf("cannot provide transient %v: it is already decorated", k), nil)
+			}
+		}
+	}
+	if n.perScope {
+		for k := range keys {
+			if k.group != "" {
+				return newErrInvalidInput(
+					fmt.Sprintf("cannot provide %v per scope: value groups are shared between scopes", k), nil)
+			}
+			if s.isDecorated(k) {
+				return newErrInvalidInput(
+					fmt.Sprintf("cannot provide %v per scope: it is already decorated", k), nil)
+			}
+		}
+	}
+
 	ctype := reflect.TypeOf(ctor)
 	if len(keys) == 0 {
 		return newErrInvalidInput(
 			fmt.Sprintf("%v must provide at least one non-error type", ctype), nil)
 	}
 
+	var replaced []*constructorNode
+	if opts.Override {
+		replaced, err = s.overriddenProviders(keys)
+		if err != nil {
+			return err
+		}
+	}
+
 	oldProviders := make(map[key][]*constructorNode)
 	for k := range keys {
 		// Cache old providers before running cycle detection.
 		oldProviders[k] = s.providers[k]
-		s.providers[k] = append(s.providers[k], n)
+		if opts.Override && k.group == "" {
+			s.providers[k] = []*constructorNode{n}
+		} else {
+			s.providers[k] = append(s.providers[k], n)
+		}
 	}
 
-	for _, s := range allScopes {
-		s.isVerifiedAcyclic = false
-		if s.deferAcyclicVerification {
+	for _, sc := range allScopes {
+		sc.isVerifiedAcyclic = false
+		if sc.deferAcyclicVerification {
 			continue
 		}
-		if ok, cycle := graph.IsAcyclic(s.gh); !ok {
+		if ok, cycle := graph.IsAcyclic(sc.gh); !ok {
 			// When a cycle is detected, recover the old providers to reset
 			// the providers map back to what it was before this node was
 			// introduced.
@@ -511,50 +848,39 @@ func (s *Scope) provide(ctor interface{}, opts provideOptions) (err error) {
 				s.providers[k] = ops
 			}
 
-			return newErrInvalidInput("this function introduces a cycle", s.cycleDetectedError(cycle))
+			return newErrInvalidInput("this function introduces a cycle", sc.cycleDetectedError(cycle))
 		}
-		s.isVerifiedAcyclic = true
+		sc.isVerifiedAcyclic = true
 	}
 
+	for _, p := range replaced {
+		p.override(keys)
+	}
+	s.removeUnusedNodes(replaced)
 	s.nodes = append(s.nodes, n)
 
 	// Record introspection info for caller if Info option is specified
 	if info := opts.Info; info != nil {
-		params := n.ParamList().DotParam()
-		results := n.ResultList().DotResult()
-
 		info.ID = (ID)(n.id)
-		info.Inputs = make([]*Input, len(params))
-		info.Outputs = make([]*Output, len(results))
-
-		for i, param := range params {
-			info.Inputs[i] = &Input{
-				t:        param.Type,
-				optional: param.Optional,
-				name:     param.Name,
-				group:    param.Group,
-			}
-		}
-
-		for i, res := range results {
-			info.Outputs[i] = &Output{
-				t:     res.Type,
-				name:  res.Name,
-				group: res.Group,
-			}
-		}
+		info.Inputs = newInputs(n.ParamList())
+		info.Outputs = newOutputs(n.ResultList())
+		info.location = newLocation(n.location)
 	}
 	return nil
 }
 
 // Builds a collection of all result types produced by this constructor.
-func (s *Scope) findAndValidateResults(rl resultList) (map[key]struct{}, error) {
+//
+// If override is set, results that are already provided to the Scope are
+// not reported as conflicts.
+func (s *Scope) findAndValidateResults(rl resultList, override bool) (map[key]struct{}, error) {
 	var err error
 	keyPaths := make(map[key]string)
 	walkResult(rl, connectionVisitor{
 		s:        s,
 		err:      &err,
 		keyPaths: keyPaths,
+		override: override,
 	})
 
 	if err != nil {
@@ -586,6 +912,9 @@ type connectionVisitor struct {
 	// constructor.
 	keyPaths map[key]string
 
+	// Whether the results replace the existing providers of their keys.
+	override bool
+
 	// We track the path to the current result here. For example, this will
 	// be, ["[1]", "Foo", "Bar"] when we're visiting Bar in,
 	//
@@ -657,7 +986,7 @@ func (cv connectionVisitor) checkKey(k key, path string) error {
 		return newErrInvalidInput(fmt.Sprintf("cannot provide %v from %v", k, path),
 			newErrInvalidInput(fmt.Sprintf("already provided by %v", conflict), nil))
 	}
-	if ps := cv.s.providers[k]; len(ps) > 0 {
+	if ps := cv.s.providers[k]; len(ps) > 0 && !cv.override {
 		cons := make([]string, len(ps))
 		for i, p := range ps {
 			cons[i] = fmt.Sprint(p.Location())
@@ -668,3 +997,60 @@ func (cv connectionVisitor) checkKey(k key, path string) error {
 	}
 	return nil
 }
+
+// overriddenProviders returns the constructors that currently provide the
+// given keys to this Scope, and would be replaced by a constructor
+// providing them with the Override option.
+//
+// It fails if any of these constructors was already called since its
+// values may have been handed out.
+func (s *Scope) overriddenProviders(keys map[key]struct{}) ([]*constructorNode, error) {
+	var replaced []*constructorNode
+	seen := make(map[*constructorNode]struct{})
+	for _, k := range sortedKeys(keys) {
+		if k.group != "" {
+			continue
+		}
+		for _, p := range s.providers[k] {
+			if p.instantiated() {
+				return nil, newErrInvalidInput(
+					fmt.Sprintf("cannot override %v: it was already built by %v", k, p.Location()), nil)
+			}
+			if _, ok := seen[p]; !ok {
+				seen[p] = struct{}{}
+				replaced = append(replaced, p)
+			}
+		}
+	}
+	return replaced, nil
+}
+
+// removeUnusedNodes removes the given constructors from the nodes of this
+// Scope if they no longer provide any values to it.
+func (s *Scope) removeUnusedNodes(ns []*constructorNode) {
+	if len(ns) == 0 {
+		return
+	}
+
+	used := make(map[*constructorNode]struct{})
+	for _, ps := range s.providers {
+		for _, p := range ps {
+			used[p] = struct{}{}
+		}
+	}
+
+	unused := make(map[*constructorNode]struct{})
+	for _, n := range ns {
+		if _, ok := used[n]; !ok {
+			unused[n] = struct{}{}
+		}
+	}
+
+	nodes := s.nodes[:0]
+	for _, n := range s.nodes {
+		if _, ok := unused[n]; !ok {
+			nodes = append(nodes, n)
+		}
+	}
+	s.nodes = nodes
+}
@@ -25,6 +25,7 @@ import (
 	"io"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -91,3 +92,23 @@ func TestExportString(t *testing.T) {
 	assert.Equal(t, fmt.Sprint(Export(true)), "Export(true)")
 	assert.Equal(t, fmt.Sprint(Export(false)), "Export(false)")
 }
+
+func TestTransientString(t *testing.T) {
+	assert.Equal(t, "Transient()", fmt.Sprint(Transient()))
+}
+
+func TestPerScopeString(t *testing.T) {
+	assert.Equal(t, "PerScope()", fmt.Sprint(PerScope()))
+}
+
+func TestOverrideString(t *testing.T) {
+	assert.Equal(t, "Override()", fmt.Sprint(Override()))
+}
+
+func TestCleanupString(t *testing.T) {
+	assert.Equal(t, "Cleanup()", fmt.Sprint(Cleanup()))
+}
+
+func TestTimeoutString(t *testing.T) {
+	assert.Equal(t, "Timeout(1.5s)", fmt.Sprint(Timeout(1500*time.Millisecond)))
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime"
+)
+
+// A Resolver is a Container or a Scope from which values can be retrieved
+// with the generic helpers of this package, such as Resolve and Supply.
+//
+// Resolver is implemented by *Container and *Scope only.
+type Resolver interface {
+	resolverScope() *Scope
+}
+
+var (
+	_ Resolver = (*Container)(nil)
+	_ Resolver = (*Scope)(nil)
+)
+
+func (c *Container) resolverScope() *Scope { return c.scope }
+
+func (s *Scope) resolverScope() *Scope { return s }
+
+// A ResolveOption modifies the default behavior of Resolve and ResolveNamed.
+type ResolveOption interface {
+	applyResolveOption(*resolveOptions)
+}
+
+type resolveOptions struct {
+	Optional bool
+}
+
+// ResolveOptional is a ResolveOption that makes Resolve report the zero
+// value of the requested type, rather than an error, if the container does
+// not know how to build it. It is the equivalent of an `optional:"true"`
+// dig.In field.
+func ResolveOptional() ResolveOption {
+	return resolveOptionalOption{}
+}
+
+type resolveOptionalOption struct{}
+
+func (resolveOptionalOption) String() string {
+	return "ResolveOptional()"
+}
+
+func (resolveOptionalOption) applyResolveOption(opts *resolveOptions) {
+	opts.Optional = true
+}
+
+// Resolve retrieves a value of type T from the given Container or Scope,
+// instantiating it and its dependencies if needed.
+//
+//	logger, err := dig.Resolve[*zap.Logger](c)
+//
+// This is equivalent to, but cheaper than,
+//
+//	var logger *zap.Logger
+//	err := c.Invoke(func(l *zap.Logger) { logger = l })
+//
+// T may also be a dig.In struct, in which case all its fields are filled
+// as they would be for a parameter of an invoked function.
+func Resolve[T any](r Resolver, opts ...ResolveOption) (T, error) {
+	return resolve[T](r.resolverScope(), "", opts)
+}
+
+// MustResolve is like Resolve, but panics if the value cannot be resolved.
+// It is intended for use in tests and program initialization.
+func MustResolve[T any](r Resolver, opts ...ResolveOption) T {
+	v, err := Resolve[T](r, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// ResolveNamed retrieves the value of type T with the given name from the
+// given Container or Scope. See also the package documentation about Named
+// Values.
+//
+//	ro, err := dig.ResolveNamed[*sql.DB](c, "ro")
+func ResolveNamed[T any](r Resolver, name string, opts ...ResolveOption) (T, error) {
+	return resolve[T](r.resolverScope(), name, opts)
+}
+
+// ResolveGroup retrieves all the values of type T that were sent to the
+// given value group. See also the package documentation about Value Groups.
+//
+//	handlers, err := dig.ResolveGroup[Handler](c, "server")
+//
+// As with dig.In fields, the group may be marked soft with the "soft"
+// modifier.
+//
+//	handlers, err := dig.ResolveGroup[Handler](c, "server,soft")
+func ResolveGroup[T any](r Resolver, group string) ([]T, error) {
+	g, err := parseGroupString(group)
+	if err != nil {
+		return nil, err
+	}
+	if g.Flatten {
+		return nil, newErrInvalidInput(
+			fmt.Sprintf("cannot use flatten in parameter value groups: group %q specifies flatten", group), nil)
+	}
+
+	p := paramGroupedSlice{
+		Group: g.Name,
+		Type:  reflect.TypeOf([]T(nil)),
+		Soft:  g.Soft,
+	}
+	v, err := r.resolverScope().resolve(p)
+	if err != nil {
+		return nil, err
+	}
+	return v.Interface().([]T), nil
+}
+
+// Supply provides the given value to the Container or Scope as a value of
+// type T. It is a shorthand for providing a constructor that returns v.
+//
+//	err := dig.Supply[io.Writer](c, os.Stdout)
+//
+// Supply accepts the same options as Provide.
+func Supply[T any](r Resolver, v T, opts ...ProvideOption) error {
+	// Report the caller of Supply as the location of the constructor,
+	// unless the user asked for a different location.
+	if pc, _, _, ok := runtime.Caller(1); ok {
+		opts = append([]ProvideOption{LocationForPC(pc)}, opts...)
+	}
+	return r.resolverScope().Provide(func() T { return v }, opts...)
+}
+
+func resolve[T any](s *Scope, name string, opts []ResolveOption) (T, error) {
+	var (
+		options resolveOptions
+		zero    T
+	)
+	for _, o := range opts {
+		o.applyResolveOption(&options)
+	}
+
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	s.mu.Lock()
+	p, err := newParam(t, s)
+	s.mu.Unlock()
+	if err != nil {
+		return zero, err
+	}
+
+	// The name and options of a dig.Lazy apply to the value it builds.
+	pl, isLazy := p.(paramLazy)
+	if isLazy {
+		p = pl.Value
+	}
+
+	switch ps := p.(type) {
+	case paramSingle:
+		ps.Name = name
+		ps.Optional = options.Optional
+		p = ps
+	case paramObject:
+		if name != "" || options.Optional {
+			return zero, newErrInvalidInput(fmt.Sprintf(
+				"cannot resolve parameter object %v with a name or as optional", t), nil)
+		}
+	}
+
+	if isLazy {
+		pl.Value = p
+		p = pl
+	}
+
+	v, err := s.resolve(p)
+	if err != nil {
+		return zero, err
+	}
+	return valueAs[T](v), nil
+}
+
+// valueAs returns the value held by v as a T. v may hold a nil interface,
+// which does not satisfy a type assertion. The zero value of T is returned
+// in that case.
+func valueAs[T any](v reflect.Value) T {
+	res, _ := v.Interface().(T)
+	return res
+}
+
+// resolve builds the given param in this Scope as if it was the sole
+// parameter of an invoked function.
+func (s *Scope) resolve(p param) (reflect.Value, error) {
+	if err := shallowCheckDependencies(s, paramList{Params: []param{p}}); err != nil {
+		return _noValue, err
+	}
+	if err := s.verifyAcyclic(); err != nil {
+		return _noValue, err
+	}
+
+	bs := newBuildState(context.Background(), s.builds)
+	defer bs.finish()
+	v, err := p.Build(s, bs)
+	if err != nil {
+		return _noValue, bs.rollback(err)
+	}
+	return v, nil
+}
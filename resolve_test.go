@@ -0,0 +1,323 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/dig"
+	"go.uber.org/dig/internal/digtest"
+)
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	t.Run("single value", func(t *testing.T) {
+		t.Parallel()
+
+		type A struct{ n int }
+		type B struct{ a *A }
+
+		var calls int
+		c := digtest.New(t)
+		c.RequireProvide(func() *A {
+			calls++
+			return &A{n: 42}
+		})
+		c.RequireProvide(func(a *A) *B { return &B{a: a} })
+
+		b, err := dig.Resolve[*B](c)
+		require.NoError(t, err)
+		assert.Equal(t, 42, b.a.n)
+
+		a := dig.MustResolve[*A](c)
+		assert.Same(t, b.a, a)
+		assert.Equal(t, 1, calls, "constructor must be called once")
+	})
+
+	t.Run("from scope", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() int { return 1 })
+		s := c.Scope("child")
+		s.RequireProvide(func(i int) string { return fmt.Sprint(i) })
+
+		v, err := dig.Resolve[string](s)
+		require.NoError(t, err)
+		assert.Equal(t, "1", v)
+
+		_, err = dig.Resolve[string](c)
+		assert.ErrorContains(t, err, "missing type: string")
+	})
+
+	t.Run("named", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() string { return "ro" }, dig.Name("ro"))
+		c.RequireProvide(func() string { return "rw" }, dig.Name("rw"))
+
+		ro, err := dig.ResolveNamed[string](c, "ro")
+		require.NoError(t, err)
+		assert.Equal(t, "ro", ro)
+
+		rw, err := dig.ResolveNamed[string](c, "rw")
+		require.NoError(t, err)
+		assert.Equal(t, "rw", rw)
+
+		_, err = dig.Resolve[string](c)
+		assert.ErrorContains(t, err, "missing type: string")
+	})
+
+	t.Run("optional", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		w, err := dig.Resolve[io.Writer](c, dig.ResolveOptional())
+		require.NoError(t, err)
+		assert.Nil(t, w)
+
+		_, err = dig.ResolveNamed[int](c, "foo", dig.ResolveOptional())
+		require.NoError(t, err)
+
+		assert.Equal(t, "ResolveOptional()", fmt.Sprint(dig.ResolveOptional()))
+	})
+
+	t.Run("named Lazy", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() string { return "unnamed" })
+		c.RequireProvide(func() string { return "ro" }, dig.Name("ro"))
+
+		l, err := dig.ResolveNamed[dig.Lazy[string]](c, "ro")
+		require.NoError(t, err)
+		assert.Equal(t, "ro", l.MustGet())
+	})
+
+	t.Run("optional Lazy", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		l, err := dig.Resolve[dig.Lazy[io.Writer]](c, dig.ResolveOptional())
+		require.NoError(t, err)
+
+		w, err := l.Get()
+		require.NoError(t, err)
+		assert.Nil(t, w)
+	})
+
+	t.Run("nil interface", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() io.Reader { return nil })
+
+		r, err := dig.Resolve[io.Reader](c)
+		require.NoError(t, err)
+		assert.Nil(t, r)
+	})
+
+	t.Run("parameter object", func(t *testing.T) {
+		t.Parallel()
+
+		type params struct {
+			dig.In
+
+			Name    string `name:"name"`
+			Missing int    `optional:"true"`
+			Values  []int  `group:"values"`
+		}
+
+		c := digtest.New(t)
+		c.RequireProvide(func() string { return "foo" }, dig.Name("name"))
+		c.RequireProvide(func() int { return 1 }, dig.Group("values"))
+		c.RequireProvide(func() int { return 2 }, dig.Group("values"))
+
+		p, err := dig.Resolve[params](c)
+		require.NoError(t, err)
+		assert.Equal(t, "foo", p.Name)
+		assert.Zero(t, p.Missing)
+		assert.ElementsMatch(t, []int{1, 2}, p.Values)
+
+		_, err = dig.ResolveNamed[params](c, "foo")
+		assert.ErrorContains(t, err, "cannot resolve parameter object")
+	})
+
+	t.Run("constructor error", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() (*bytes.Buffer, error) {
+			return nil, errors.New("great sadness")
+		})
+
+		_, err := dig.Resolve[*bytes.Buffer](c)
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "great sadness")
+		assert.Panics(t, func() { dig.MustResolve[*bytes.Buffer](c) })
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		t.Parallel()
+
+		type A struct{}
+		type B struct{}
+
+		c := digtest.New(t, dig.DeferAcyclicVerification())
+		c.RequireProvide(func(*B) *A { return &A{} })
+		c.RequireProvide(func(*A) *B { return &B{} })
+
+		_, err := dig.Resolve[*A](c)
+		require.Error(t, err)
+		assert.True(t, dig.IsCycleDetected(err))
+	})
+
+	t.Run("invalid type", func(t *testing.T) {
+		t.Parallel()
+
+		type out struct {
+			dig.Out
+
+			A int
+		}
+
+		c := digtest.New(t)
+		_, err := dig.Resolve[out](c)
+		assert.ErrorContains(t, err, "cannot depend on result objects")
+	})
+}
+
+func TestResolveGroup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("values", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() string { return "a" }, dig.Group("letters"))
+		c.RequireProvide(func() string { return "b" }, dig.Group("letters"))
+
+		letters, err := dig.ResolveGroup[string](c, "letters")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"a", "b"}, letters)
+
+		empty, err := dig.ResolveGroup[string](c, "digits")
+		require.NoError(t, err)
+		assert.Empty(t, empty)
+	})
+
+	t.Run("soft", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() string { return "a" }, dig.Group("letters"))
+
+		letters, err := dig.ResolveGroup[string](c, "letters,soft")
+		require.NoError(t, err)
+		assert.Empty(t, letters, "soft group must not call constructors")
+
+		_, err = dig.ResolveGroup[string](c, "letters")
+		require.NoError(t, err)
+
+		letters, err = dig.ResolveGroup[string](c, "letters,soft")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a"}, letters)
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		_, err := dig.ResolveGroup[string](c, "letters,flatten")
+		assert.ErrorContains(t, err, "cannot use flatten")
+
+		_, err = dig.ResolveGroup[string](c, "letters,foo")
+		assert.ErrorContains(t, err, `invalid option "foo"`)
+	})
+
+	t.Run("constructor error", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() (string, error) {
+			return "", errors.New("great sadness")
+		}, dig.Group("letters"))
+
+		_, err := dig.ResolveGroup[string](c, "letters")
+		assert.ErrorContains(t, err, "could not build value group")
+	})
+}
+
+func TestSupply(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		c := digtest.New(t)
+		require.NoError(t, dig.Supply[io.Writer](c, &buf))
+		require.NoError(t, dig.Supply(c, "hello", dig.Name("greeting")))
+
+		c.RequireInvoke(func(w io.Writer) {
+			assert.Same(t, &buf, w)
+		})
+
+		greeting, err := dig.ResolveNamed[string](c, "greeting")
+		require.NoError(t, err)
+		assert.Equal(t, "hello", greeting)
+	})
+
+	t.Run("location", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		require.NoError(t, dig.Supply(c, 42))
+
+		err := dig.Supply(c, 43)
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "TestSupply.func2")
+		assert.ErrorContains(t, err, "already provided by")
+	})
+
+	t.Run("scope", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		s := c.Scope("child")
+		require.NoError(t, dig.Supply(s, 42))
+
+		v, err := dig.Resolve[int](s)
+		require.NoError(t, err)
+		assert.Equal(t, 42, v)
+
+		_, err = dig.Resolve[int](c)
+		assert.Error(t, err)
+	})
+}
@@ -63,6 +63,10 @@ type resultOptions struct {
 	Name  string
 	Group string
 	As    []interface{}
+
+	// If set, a func() or func() error returned after all other results
+	// is a cleanup function rather than a value to provide.
+	Cleanup bool
 }
 
 // newResult builds a result from the given type.
@@ -199,8 +203,13 @@ type resultList struct {
 
 	// For each item at index i returned by the constructor, resultIndexes[i]
 	// is the index in .Results for the corresponding result object.
-	// resultIndexes[i] is -1 for errors returned by constructors.
+	// resultIndexes[i] is -1 for errors and cleanup functions returned by
+	// constructors.
 	resultIndexes []int
+
+	// Index of the cleanup function returned by the constructor, or -1 if
+	// it doesn't return one.
+	cleanupIndex int
 }
 
 func (rl resultList) DotResult() []*dot.Result {
@@ -217,12 +226,16 @@ func newResultList(ctype reflect.Type, opts resultOptions) (resultList, error) {
 		ctype:         ctype,
 		Results:       make([]result, 0, numOut),
 		resultIndexes: make([]int, numOut),
+		cleanupIndex:  -1,
+	}
+	if opts.Cleanup {
+		rl.cleanupIndex = findCleanupResult(ctype)
 	}
 
 	resultIdx := 0
 	for i := 0; i < numOut; i++ {
 		t := ctype.Out(i)
-		if isError(t) {
+		if isError(t) || i == rl.cleanupIndex {
 			rl.resultIndexes[i] = -1
 			continue
 		}
@@ -240,6 +253,35 @@ func newResultList(ctype reflect.Type, opts resultOptions) (resultList, error) {
 	return rl, nil
 }
 
+// findCleanupResult returns the index of the cleanup function returned by
+// a constructor of type ctype, or -1 if it doesn't return one.
+//
+// A cleanup function is a func() or func() error that comes after all
+// other non-error results. Constructors must return at least one other
+// value.
+func findCleanupResult(ctype reflect.Type) int {
+	last, count := -1, 0
+	for i := 0; i < ctype.NumOut(); i++ {
+		if !isError(ctype.Out(i)) {
+			last = i
+			count++
+		}
+	}
+	if count < 2 || !isCleanupType(ctype.Out(last)) {
+		return -1
+	}
+	return last
+}
+
+// Cleanup returns the cleanup function among the values returned by the
+// constructor, or nil if there isn't one.
+func (rl resultList) Cleanup(values []reflect.Value) func() error {
+	if rl.cleanupIndex < 0 {
+		return nil
+	}
+	return newCleanupFunc(values[rl.cleanupIndex])
+}
+
 func (resultList) Extract(containerWriter, bool, reflect.Value) {
 	digerror.BugPanicf("resultList.Extract() must never be called")
 }
@@ -472,11 +514,13 @@ func (rt resultGrouped) DotResult() []*dot.Result {
 			Type:  rt.Type,
 			Group: rt.Group,
 		},
+		Flatten: rt.Flatten,
 	})
 
 	for _, asType := range rt.As {
 		dotResults = append(dotResults, &dot.Result{
-			Node: &dot.Node{Type: asType, Group: rt.Group},
+			Node:    &dot.Node{Type: asType, Group: rt.Group},
+			Flatten: rt.Flatten,
 		})
 	}
 	return dotResults
@@ -74,6 +74,56 @@ func TestResultListExtractFails(t *testing.T) {
 	})
 }
 
+func TestFindCleanupResult(t *testing.T) {
+	tests := []struct {
+		desc string
+		give interface{}
+		want int
+	}{
+		{
+			desc: "no cleanup",
+			give: func() (io.Writer, error) { panic("do not call") },
+			want: -1,
+		},
+		{
+			desc: "func()",
+			give: func() (io.Writer, func()) { panic("do not call") },
+			want: 1,
+		},
+		{
+			desc: "func() error before error",
+			give: func() (io.Writer, func() error, error) { panic("do not call") },
+			want: 1,
+		},
+		{
+			desc: "only func()",
+			give: func() (func(), error) { panic("do not call") },
+			want: -1,
+		},
+		{
+			desc: "func() not last",
+			give: func() (func(), io.Writer) { panic("do not call") },
+			want: -1,
+		},
+		{
+			desc: "other func type",
+			give: func() (io.Writer, func(int)) { panic("do not call") },
+			want: -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ctype := reflect.TypeOf(tt.give)
+			assert.Equal(t, tt.want, findCleanupResult(ctype))
+
+			rl, err := newResultList(ctype, resultOptions{Cleanup: true})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, rl.cleanupIndex)
+		})
+	}
+}
+
 func TestNewResultErrors(t *testing.T) {
 	type outPtr struct{ *Out }
 	type out struct{ Out }
@@ -0,0 +1,139 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"go.uber.org/dig/internal/digclock"
+)
+
+// RetryPolicy specifies how a constructor provided with the Retry option
+// is called again after it fails.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of calls made to the constructor,
+	// including the first one. It must be at least 1.
+	MaxAttempts int
+
+	// Backoff is the time to wait before the first retry. The wait
+	// doubles after every retry, up to MaxBackoff if it's set.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+
+	// ShouldRetry reports whether a call that failed with the given error
+	// should be retried. All errors are retried if it's nil.
+	ShouldRetry func(error) bool
+}
+
+// Retry is a ProvideOption that calls the constructor again when it
+// returns an error, as specified by the given policy.
+//
+// Only the constructor itself is called again: its dependencies are built
+// once, before the first attempt. Constructors that panic are not retried.
+// Every attempt is reported to the callbacks of the constructor. If all of
+// them fail, the error of the last attempt is returned, along with the
+// errors of the previous ones.
+//
+//	c.Provide(NewSidecarClient, dig.Retry(dig.RetryPolicy{
+//	  MaxAttempts: 5,
+//	  Backoff:     100 * time.Millisecond,
+//	  MaxBackoff:  2 * time.Second,
+//	}))
+//
+// Waits are cut short if the context given to InvokeContext is done, or if
+// the Timeout of the constructor elapses. The Timeout bounds all the
+// attempts together.
+func Retry(policy RetryPolicy) ProvideOption {
+	return provideRetryOption(policy)
+}
+
+type provideRetryOption RetryPolicy
+
+func (o provideRetryOption) String() string {
+	return fmt.Sprintf("Retry(MaxAttempts: %d, Backoff: %v, MaxBackoff: %v)", o.MaxAttempts, o.Backoff, o.MaxBackoff)
+}
+
+func (o provideRetryOption) applyProvideOption(opts *provideOptions) {
+	policy := RetryPolicy(o)
+	opts.Retry = &policy
+}
+
+func (p *RetryPolicy) validate() error {
+	switch {
+	case p.MaxAttempts < 1:
+		return newErrInvalidInput(
+			fmt.Sprintf("invalid dig.Retry: MaxAttempts must be at least 1, got %d", p.MaxAttempts), nil)
+	case p.Backoff < 0 || p.MaxBackoff < 0:
+		return newErrInvalidInput("invalid dig.Retry: backoff must not be negative", nil)
+	}
+	return nil
+}
+
+// retries reports whether a call that failed with err on the given
+// attempt must be retried. A nil policy never retries.
+func (p *RetryPolicy) retries(attempt int, err error) bool {
+	if p == nil || attempt >= p.MaxAttempts {
+		return false
+	}
+	// Panics and cancellations are not retried.
+	var cf errConstructorFailed
+	if !errors.As(err, &cf) {
+		return false
+	}
+	return p.ShouldRetry == nil || p.ShouldRetry(cf.Reason)
+}
+
+// backoff returns the time to wait after the given failed attempt.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.Backoff
+	for i := 1; i < attempt && d > 0; i++ {
+		if d > math.MaxInt64/2 {
+			d = math.MaxInt64
+			break
+		}
+		d *= 2
+		if p.MaxBackoff > 0 && d >= p.MaxBackoff {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return d
+}
+
+// sleep waits for d on the clock. It returns the error of ctx early if ctx
+// is done first.
+func sleep(ctx context.Context, clock digclock.Clock, d time.Duration) error {
+	if err := ctx.Err(); err != nil || d <= 0 {
+		return err
+	}
+	select {
+	case <-clock.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,292 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/dig"
+	"go.uber.org/dig/internal/digclock"
+	"go.uber.org/dig/internal/digtest"
+)
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+
+	type A struct{}
+	type B struct{}
+
+	t.Run("succeeds after retries", func(t *testing.T) {
+		t.Parallel()
+
+		clock := digclock.NewMock()
+		c := digtest.New(t, dig.SetClock(clock))
+		start := clock.Now()
+
+		var (
+			calls   int
+			before  []int
+			reports []dig.CallbackInfo
+		)
+		c.RequireProvide(func() B { return B{} })
+		c.RequireProvide(func(B) (A, error) {
+			calls++
+			if calls < 3 {
+				return A{}, fmt.Errorf("not ready %d", calls)
+			}
+			return A{}, nil
+		},
+			dig.Retry(dig.RetryPolicy{MaxAttempts: 5, Backoff: time.Second}),
+			dig.WithProviderBeforeCallback(func(bci dig.BeforeCallbackInfo) {
+				before = append(before, bci.Attempt)
+			}),
+			dig.WithProviderCallback(func(ci dig.CallbackInfo) {
+				reports = append(reports, ci)
+			}),
+		)
+
+		c.RequireInvoke(func(A) {})
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, []int{1, 2, 3}, before)
+		require.Len(t, reports, 3)
+		for i, ci := range reports {
+			assert.Equal(t, i+1, ci.Attempt)
+		}
+		assert.ErrorContains(t, reports[0].Error, "not ready 1")
+		assert.ErrorContains(t, reports[1].Error, "not ready 2")
+		assert.NoError(t, reports[2].Error)
+		assert.Equal(t, 3*time.Second, clock.Since(start), "must wait 1s, then 2s")
+
+		stats := c.Stats().Constructors[1]
+		assert.Equal(t, 3, stats.Calls)
+		assert.Equal(t, 2, stats.Errors)
+	})
+
+	t.Run("gives up", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t, dig.SetClock(digclock.NewMock()))
+		var calls int
+		c.RequireProvide(func() (A, error) {
+			calls++
+			return A{}, fmt.Errorf("great sadness %d", calls)
+		}, dig.Retry(dig.RetryPolicy{MaxAttempts: 3, Backoff: time.Second}))
+
+		err := c.Invoke(func(A) {})
+		require.Error(t, err)
+		assert.Equal(t, 3, calls)
+		assert.Contains(t, err.Error(), "failed after 3 attempts (attempt 1: great sadness 1; attempt 2: great sadness 2): ")
+		assert.Contains(t, err.Error(), "received non-nil error from function")
+		assert.Equal(t, "great sadness 3", dig.RootCause(err).Error())
+	})
+
+	t.Run("backoff is capped", func(t *testing.T) {
+		t.Parallel()
+
+		clock := digclock.NewMock()
+		c := digtest.New(t, dig.SetClock(clock))
+		start := clock.Now()
+		c.RequireProvide(func() (A, error) {
+			return A{}, errors.New("great sadness")
+		}, dig.Retry(dig.RetryPolicy{MaxAttempts: 5, Backoff: time.Second, MaxBackoff: 3 * time.Second}))
+
+		require.Error(t, c.Invoke(func(A) {}))
+		assert.Equal(t, (1+2+3+3)*time.Second, clock.Since(start))
+	})
+
+	t.Run("errors that should not be retried", func(t *testing.T) {
+		t.Parallel()
+
+		permanent := errors.New("permanent")
+		c := digtest.New(t, dig.SetClock(digclock.NewMock()))
+		var calls int
+		c.RequireProvide(func() (A, error) {
+			calls++
+			if calls == 1 {
+				return A{}, errors.New("transient")
+			}
+			return A{}, permanent
+		}, dig.Retry(dig.RetryPolicy{
+			MaxAttempts: 5,
+			ShouldRetry: func(err error) bool { return !errors.Is(err, permanent) },
+		}))
+
+		err := c.Invoke(func(A) {})
+		require.Error(t, err)
+		assert.Equal(t, 2, calls)
+		assert.ErrorIs(t, err, permanent)
+		assert.Contains(t, err.Error(), "failed after 2 attempts (attempt 1: transient)")
+	})
+
+	t.Run("single attempt", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		giveErr := errors.New("great sadness")
+		c.RequireProvide(func() (A, error) {
+			return A{}, giveErr
+		}, dig.Retry(dig.RetryPolicy{MaxAttempts: 1}))
+
+		err := c.Invoke(func(A) {})
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), "attempts")
+		assert.Equal(t, giveErr, dig.RootCause(err))
+	})
+
+	t.Run("panics are not retried", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t, dig.RecoverFromPanics())
+		var calls int
+		c.RequireProvide(func() A {
+			calls++
+			panic("great sadness")
+		}, dig.Retry(dig.RetryPolicy{MaxAttempts: 3}))
+
+		err := c.Invoke(func(A) {})
+		var pe dig.PanicError
+		require.ErrorAs(t, err, &pe)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("context done while waiting", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		c := digtest.New(t)
+		var calls int
+		c.RequireProvide(func() (A, error) {
+			calls++
+			return A{}, errors.New("great sadness")
+		}, dig.Retry(dig.RetryPolicy{
+			MaxAttempts: 3,
+			Backoff:     time.Hour,
+			ShouldRetry: func(error) bool {
+				cancel()
+				return true
+			},
+		}))
+
+		err := c.InvokeContext(ctx, func(A) {})
+		require.Error(t, err)
+		assert.Equal(t, 1, calls)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Contains(t, err.Error(), "failed after 2 attempts (attempt 1: great sadness): stopped before calling")
+	})
+
+	t.Run("Timeout bounds all attempts", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		var calls int
+		c.RequireProvide(func() (A, error) {
+			calls++
+			return A{}, errors.New("not ready")
+		},
+			dig.Retry(dig.RetryPolicy{MaxAttempts: 3, Backoff: time.Hour}),
+			dig.Timeout(20*time.Millisecond),
+		)
+
+		start := time.Now()
+		err := c.Invoke(func(A) {})
+		require.Error(t, err)
+		assert.Less(t, time.Since(start), time.Minute, "backoff must be cut short")
+		assert.Equal(t, 1, calls)
+
+		var cerr dig.CanceledError
+		require.ErrorAs(t, err, &cerr)
+		assert.Equal(t, 20*time.Millisecond, cerr.Timeout)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("Timeout is not per attempt", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		var calls atomic.Int32
+		c.RequireProvide(func() (A, error) {
+			// Every attempt returns in time on its own, but not all of
+			// them together.
+			time.Sleep(30 * time.Millisecond)
+			if calls.Add(1) < 3 {
+				return A{}, errors.New("not ready")
+			}
+			return A{}, nil
+		},
+			dig.Retry(dig.RetryPolicy{MaxAttempts: 3}),
+			dig.Timeout(50*time.Millisecond),
+		)
+
+		err := c.Invoke(func(A) {})
+		require.Error(t, err)
+
+		var cerr dig.CanceledError
+		require.ErrorAs(t, err, &cerr)
+		assert.Equal(t, 50*time.Millisecond, cerr.Timeout)
+	})
+
+	t.Run("Clone", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t, dig.SetClock(digclock.NewMock()))
+		var calls int
+		c.RequireProvide(func() (A, error) {
+			calls++
+			if calls < 3 {
+				return A{}, errors.New("not ready")
+			}
+			return A{}, nil
+		}, dig.Retry(dig.RetryPolicy{MaxAttempts: 3, Backoff: time.Second}))
+
+		require.NoError(t, c.Clone().Invoke(func(A) {}))
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("invalid policies", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		err := c.Provide(func() A { return A{} }, dig.Retry(dig.RetryPolicy{}))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid dig.Retry: MaxAttempts must be at least 1, got 0")
+
+		err = c.Provide(func() A { return A{} }, dig.Retry(dig.RetryPolicy{MaxAttempts: 2, Backoff: -time.Second}))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid dig.Retry: backoff must not be negative")
+	})
+}
+
+func TestRetryString(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t,
+		"Retry(MaxAttempts: 3, Backoff: 1s, MaxBackoff: 1m0s)",
+		fmt.Sprint(dig.Retry(dig.RetryPolicy{MaxAttempts: 3, Backoff: time.Second, MaxBackoff: time.Minute})))
+}
@@ -26,6 +26,7 @@ import (
 	"math/rand"
 	"reflect"
 	"sort"
+	"sync"
 	"time"
 
 	"go.uber.org/dig/internal/digclock"
@@ -45,6 +46,12 @@ type Scope struct {
 
 	// Name of the Scope
 	name string
+
+	// mu guards the providers, decorators and graph of this Scope, as well
+	// as the structure of the Scope tree. It is shared by all Scopes of the
+	// same Container since changes to one Scope may affect the others.
+	mu *sync.RWMutex
+
 	// Mapping from key to all the constructor node that can provide a value for that
 	// key.
 	providers map[key][]*constructorNode
@@ -62,12 +69,21 @@ type Scope struct {
 	// Values that generated directly in the Scope.
 	values map[key]reflect.Value
 
+	// Values that were generated for this Scope by per-scope constructors.
+	// Unlike values, these are not visible to descendant Scopes.
+	scopedValues map[key]reflect.Value
+
 	// Values groups that generated directly in the Scope.
 	groups map[key][]reflect.Value
 
 	// Values groups that generated via decoraters in the Scope.
 	decoratedGroups map[key]reflect.Value
 
+	// valuesMu guards the values, value groups and their decorated
+	// counterparts stored in this Scope, as well as the source of
+	// randomness.
+	valuesMu sync.Mutex
+
 	// Source of randomness.
 	rand *rand.Rand
 
@@ -95,6 +111,33 @@ type Scope struct {
 
 	// clockSrc stores the source of time. Defaults to system clock.
 	clockSrc digclock.Clock
+
+	// Workers used to build dependencies in parallel, if any. Shared by
+	// all Scopes of a Container.
+	workers *workerPool
+
+	// Functions to run when this Scope is closed, in the order they were
+	// registered. Guarded by valuesMu.
+	cleanups []*cleanup
+
+	// Counter used to order cleanups across all Scopes of a Container.
+	cleanupSeq *uint64
+
+	// Builds running in any Scope of the Container.
+	builds *buildTracker
+
+	// Whether values implementing io.Closer are closed with the Scope.
+	trackClosers bool
+
+	// Handlers of the events emitted by the Container.
+	eventHandlers []EventHandler
+
+	// Tracer recording the work done by the Container, if any.
+	tracer *Tracer
+
+	// Scope this Scope was cloned from, if any. Graph nodes shared with it
+	// have the same order in both graphs.
+	clonedFrom *Scope
 }
 
 func newScope() *Scope {
@@ -102,12 +145,16 @@ func newScope() *Scope {
 		providers:       make(map[key][]*constructorNode),
 		decorators:      make(map[key]*decoratorNode),
 		values:          make(map[key]reflect.Value),
+		scopedValues:    make(map[key]reflect.Value),
 		decoratedValues: make(map[key]reflect.Value),
 		groups:          make(map[key][]reflect.Value),
 		decoratedGroups: make(map[key]reflect.Value),
 		invokerFn:       defaultInvoker,
 		rand:            rand.New(rand.NewSource(time.Now().UnixNano())),
 		clockSrc:        digclock.System,
+		mu:              new(sync.RWMutex),
+		cleanupSeq:      new(uint64),
+		builds:          new(buildTracker),
 	}
 	s.gh = newGraphHolder(s)
 	return s
@@ -119,13 +166,31 @@ func newScope() *Scope {
 // However, no modifications made to the child scope being created will be propagated
 // to the parent Scope.
 func (s *Scope) Scope(name string, opts ...ScopeOption) *Scope {
+	child := s.newChild(name, opts)
+	if s.emitting() {
+		s.emit(ScopeCreated{Name: name, Parent: s.name})
+	}
+	return child
+}
+
+func (s *Scope) newChild(name string, opts []ScopeOption) *Scope {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	child := newScope()
+	child.mu = s.mu
 	child.name = name
 	child.parentScope = s
 	child.invokerFn = s.invokerFn
 	child.clockSrc = s.clockSrc
 	child.deferAcyclicVerification = s.deferAcyclicVerification
 	child.recoverFromPanics = s.recoverFromPanics
+	child.workers = s.workers
+	child.cleanupSeq = s.cleanupSeq
+	child.builds = s.builds
+	child.trackClosers = s.trackClosers
+	child.eventHandlers = s.eventHandlers
+	child.tracer = s.tracer
 
 	// child copies the parent's graph nodes.
 	for _, node := range s.gh.nodes {
@@ -161,6 +226,38 @@ func (s *Scope) appendSubscopes(dest []*Scope) []*Scope {
 	return dest
 }
 
+// relatedScopes returns this Scope, its ancestors and its descendants:
+// the Scopes whose values may affect or be affected by this one.
+func (s *Scope) relatedScopes() []*Scope {
+	return append(s.ancestors(), s.appendSubscopes(nil)[1:]...)
+}
+
+// isDecorated reports whether values for k are decorated in any Scope
+// related to this one. The caller must hold s.mu.
+func (s *Scope) isDecorated(k key) bool {
+	for _, s := range s.relatedScopes() {
+		if _, ok := s.decorators[k]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// findUncachedProvider looks for a transient or per-scope constructor
+// that provides values for k in any Scope related to this one. The values
+// of such constructors aren't shared by the whole Scope tree. The caller
+// must hold s.mu.
+func (s *Scope) findUncachedProvider(k key) (provider, bool) {
+	for _, s := range s.relatedScopes() {
+		for _, p := range s.getProviders(k) {
+			if p.Transient() || p.PerScope() {
+				return p, true
+			}
+		}
+	}
+	return nil, false
+}
+
 func (s *Scope) storesToRoot() []containerStore {
 	scopes := s.ancestors()
 	stores := make([]containerStore, len(scopes))
@@ -171,6 +268,9 @@ func (s *Scope) storesToRoot() []containerStore {
 }
 
 func (s *Scope) knownTypes() []reflect.Type {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	typeSet := make(map[reflect.Type]struct{}, len(s.providers))
 	for k := range s.providers {
 		typeSet[k.t] = struct{}{}
@@ -185,65 +285,119 @@ func (s *Scope) knownTypes() []reflect.Type {
 }
 
 func (s *Scope) getValue(name string, t reflect.Type) (v reflect.Value, ok bool) {
+	s.valuesMu.Lock()
+	defer s.valuesMu.Unlock()
+
 	v, ok = s.values[key{name: name, t: t}]
 	return
 }
 
+func (s *Scope) getScopedValue(name string, t reflect.Type) (v reflect.Value, ok bool) {
+	s.valuesMu.Lock()
+	defer s.valuesMu.Unlock()
+
+	v, ok = s.scopedValues[key{name: name, t: t}]
+	return
+}
+
 func (s *Scope) getDecoratedValue(name string, t reflect.Type) (v reflect.Value, ok bool) {
+	s.valuesMu.Lock()
+	defer s.valuesMu.Unlock()
+
 	v, ok = s.decoratedValues[key{name: name, t: t}]
 	return
 }
 
 func (s *Scope) setValue(name string, t reflect.Type, v reflect.Value) {
+	s.valuesMu.Lock()
+	defer s.valuesMu.Unlock()
+
 	s.values[key{name: name, t: t}] = v
 }
 
 func (s *Scope) setDecoratedValue(name string, t reflect.Type, v reflect.Value) {
+	s.valuesMu.Lock()
+	defer s.valuesMu.Unlock()
+
 	s.decoratedValues[key{name: name, t: t}] = v
 }
 
 func (s *Scope) getValueGroup(name string, t reflect.Type) []reflect.Value {
-	items := s.groups[key{group: name, t: t}]
+	s.valuesMu.Lock()
+	defer s.valuesMu.Unlock()
+
+	var items []reflect.Value
+	for _, v := range s.groups[key{group: name, t: t}] {
+		// Values of constructors that were rolled back are invalid.
+		if v.IsValid() {
+			items = append(items, v)
+		}
+	}
 	// shuffle the list so users don't rely on the ordering of grouped values
 	return shuffledCopy(s.rand, items)
 }
 
 func (s *Scope) getDecoratedValueGroup(name string, t reflect.Type) (reflect.Value, bool) {
+	s.valuesMu.Lock()
+	defer s.valuesMu.Unlock()
+
 	items, ok := s.decoratedGroups[key{group: name, t: t}]
 	return items, ok
 }
 
 func (s *Scope) submitGroupedValue(name string, t reflect.Type, v reflect.Value) {
+	s.valuesMu.Lock()
+	defer s.valuesMu.Unlock()
+
 	k := key{group: name, t: t}
 	s.groups[k] = append(s.groups[k], v)
 }
 
 func (s *Scope) submitDecoratedGroupedValue(name string, t reflect.Type, v reflect.Value) {
+	s.valuesMu.Lock()
+	defer s.valuesMu.Unlock()
+
 	k := key{group: name, t: t}
 	s.decoratedGroups[k] = v
 }
 
 func (s *Scope) getValueProviders(name string, t reflect.Type) []provider {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	return s.getProviders(key{name: name, t: t})
 }
 
 func (s *Scope) getGroupProviders(name string, t reflect.Type) []provider {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	return s.getProviders(key{group: name, t: t})
 }
 
 func (s *Scope) getValueDecorator(name string, t reflect.Type) (decorator, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	return s.getDecorators(key{name: name, t: t})
 }
 
 func (s *Scope) getGroupDecorator(name string, t reflect.Type) (decorator, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	return s.getDecorators(key{group: name, t: t})
 }
 
+// getDecorators returns the decorator for the given key in this Scope.
+// The caller must hold s.mu.
 func (s *Scope) getDecorators(k key) (decorator, bool) {
 	d, found := s.decorators[k]
 	return d, found
 }
 
+// getProviders returns the providers for the given key in this Scope.
+// The caller must hold s.mu.
 func (s *Scope) getProviders(k key) []provider {
 	nodes := s.providers[k]
 	providers := make([]provider, len(nodes))
@@ -253,14 +407,15 @@ func (s *Scope) getProviders(k key) []provider {
 	return providers
 }
 
-func (s *Scope) getAllGroupProviders(name string, t reflect.Type) []provider {
-	return s.getAllProviders(key{group: name, t: t})
-}
-
 func (s *Scope) getAllValueProviders(name string, t reflect.Type) []provider {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	return s.getAllProviders(key{name: name, t: t})
 }
 
+// getAllProviders returns the providers for the given key in this Scope
+// and all its ancestors. The caller must hold s.mu.
 func (s *Scope) getAllProviders(k key) []provider {
 	allScopes := s.ancestors()
 	var providers []provider
@@ -278,6 +433,14 @@ func (s *Scope) clock() digclock.Clock {
 	return s.clockSrc
 }
 
+func (s *Scope) workerPool() *workerPool {
+	return s.workers
+}
+
+func (s *Scope) asScope() *Scope {
+	return s
+}
+
 // adds a new graphNode to this Scope and all of its descendent
 // scope.
 func (s *Scope) newGraphNode(wrapped interface{}, orders map[*Scope]int) {
@@ -313,6 +476,11 @@ func (s *Scope) rootScope() *Scope {
 
 // String representation of the entire Scope
 func (s *Scope) String() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	s.valuesMu.Lock()
+	defer s.valuesMu.Unlock()
+
 	b := &bytes.Buffer{}
 	fmt.Fprintln(b, "nodes: {")
 	for k, vs := range s.providers {
@@ -326,8 +494,14 @@ func (s *Scope) String() string {
 	for k, v := range s.values {
 		fmt.Fprintln(b, "\t", k, "=>", v)
 	}
+	for k, v := range s.scopedValues {
+		fmt.Fprintln(b, "\t", k, "=>", v)
+	}
 	for k, vs := range s.groups {
 		for _, v := range vs {
+			if !v.IsValid() {
+				continue
+			}
 			fmt.Fprintln(b, "\t", k, "=>", v)
 		}
 	}
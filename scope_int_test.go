@@ -24,6 +24,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestScopeAncestorsAndStoresToRoot(t *testing.T) {
@@ -35,3 +36,27 @@ func TestScopeAncestorsAndStoresToRoot(t *testing.T) {
 	assert.Equal(t, []containerStore{s3, s2, s1, c.scope}, s3.storesToRoot())
 	assert.Equal(t, []*Scope{s3, s2, s1, c.scope}, s3.ancestors())
 }
+
+func TestScopeClone(t *testing.T) {
+	c := New()
+	s1 := c.Scope("child1")
+	s2 := s1.Scope("child2")
+
+	clone := c.Clone()
+	require.Len(t, clone.scope.childScopes, 1)
+	cs1 := clone.scope.childScopes[0]
+	require.Len(t, cs1.childScopes, 1)
+	cs2 := cs1.childScopes[0]
+
+	assert.Equal(t, "child1", cs1.name)
+	assert.Equal(t, "child2", cs2.name)
+	assert.Same(t, clone.scope, cs1.parentScope)
+	assert.Same(t, s2, cs2.clonedFrom)
+	assert.Same(t, clone.scope.mu, cs2.mu)
+	assert.NotSame(t, c.scope.mu, cs2.mu)
+
+	sc := s1.Clone()
+	assert.Same(t, c.scope, sc.parentScope)
+	assert.Same(t, c.scope.mu, sc.mu)
+	assert.Contains(t, c.scope.childScopes, sc)
+}
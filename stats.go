@@ -0,0 +1,318 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig
+
+import (
+	"encoding/json"
+	"sync/atomic"
+	"time"
+)
+
+// Stats holds statistics on the calls made by a Container to its
+// constructors and decorators. See Container.Stats.
+type Stats struct {
+	// Constructors provided to the Container and its Scopes, in the same
+	// order as Container.Providers.
+	Constructors []FuncStats
+
+	// Decorators registered with the Container and its Scopes, in the
+	// same order as Container.Decorators.
+	Decorators []FuncStats
+
+	// CriticalPath is the chain of constructors with the largest total
+	// Self time such that each constructor depends on the next one. It
+	// starts with the constructor closest to the invoked functions.
+	//
+	// With ParallelConstruction, independent dependencies are built at
+	// the same time, but the constructors of this chain still run one
+	// after another: no Invoke that needs all of them can be faster.
+	// Constructors that were never called and Lazy dependencies aren't
+	// part of the chain.
+	CriticalPath []FuncStats
+
+	// CriticalPathTime is the sum of the Self time of the constructors in
+	// CriticalPath.
+	CriticalPathTime time.Duration
+}
+
+// FuncStats holds statistics on the calls made to a constructor or
+// decorator.
+type FuncStats struct {
+	// ID of the constructor or decorator. This is the same ID that is
+	// reported by Container.Providers and Container.Decorators.
+	ID ID
+
+	// Location of the constructor or decorator.
+	Location Location
+
+	// Name of the Scope the constructor or decorator was added to.
+	Scope string
+
+	// Number of times the function was called, and how many of these
+	// calls returned an error or panicked.
+	Calls  int
+	Errors int
+	Panics int
+
+	// Total time spent in the function over all calls.
+	Self time.Duration
+
+	// Total time spent in the calls, including the time spent building
+	// the dependencies of the function before each call.
+	Total time.Duration
+}
+
+// callStats records the calls made to a function. It is safe for
+// concurrent use.
+type callStats struct {
+	calls  atomic.Int64
+	errors atomic.Int64
+	panics atomic.Int64
+	self   atomic.Int64 // nanoseconds
+	total  atomic.Int64 // nanoseconds
+}
+
+// record records a call to the function. self is the time spent in the
+// function and total also includes the time spent building its
+// dependencies beforehand. err is the error the call failed with, if any.
+func (cs *callStats) record(self, total time.Duration, err error, panicked bool) {
+	cs.calls.Add(1)
+	cs.self.Add(int64(self))
+	cs.total.Add(int64(total))
+	switch {
+	case panicked:
+		cs.panics.Add(1)
+	case err != nil:
+		cs.errors.Add(1)
+	}
+}
+
+// Calls returns the number of calls recorded so far.
+func (cs *callStats) Calls() int {
+	return int(cs.calls.Load())
+}
+
+// Runtime returns the total time spent in the function in the calls
+// recorded so far.
+func (cs *callStats) Runtime() time.Duration {
+	return time.Duration(cs.self.Load())
+}
+
+func (cs *callStats) funcStats() FuncStats {
+	return FuncStats{
+		Calls:  int(cs.calls.Load()),
+		Errors: int(cs.errors.Load()),
+		Panics: int(cs.panics.Load()),
+		Self:   time.Duration(cs.self.Load()),
+		Total:  time.Duration(cs.total.Load()),
+	}
+}
+
+// Stats returns statistics on the calls made so far to the constructors
+// and decorators of the Container and its Scopes.
+//
+// To publish them with the expvar package, use StatsVar.
+func (c *Container) Stats() Stats {
+	return c.scope.Stats()
+}
+
+// Stats returns statistics on the calls made so far to the constructors
+// and decorators of this Scope and its descendants.
+func (s *Scope) Stats() Stats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	subscopes := make(map[*Scope]struct{})
+	for _, sc := range s.appendSubscopes(nil) {
+		subscopes[sc] = struct{}{}
+	}
+
+	var (
+		stats Stats
+		ctors []*constructorNode
+	)
+	for _, sc := range s.rootScope().appendSubscopes(nil) {
+		for _, n := range sc.nodes {
+			if _, ok := subscopes[n.OrigScope()]; !ok {
+				continue
+			}
+			fs := n.stats.funcStats()
+			fs.ID = ID(n.id)
+			fs.Location = newLocation(n.location)
+			fs.Scope = n.OrigScope().name
+			stats.Constructors = append(stats.Constructors, fs)
+			ctors = append(ctors, n)
+		}
+	}
+	for _, sc := range s.appendSubscopes(nil) {
+		for _, d := range sc.decoratorNodes() {
+			fs := d.stats.funcStats()
+			fs.ID = ID(d.id)
+			fs.Location = newLocation(d.location)
+			fs.Scope = d.s.name
+			stats.Decorators = append(stats.Decorators, fs)
+		}
+	}
+
+	for _, i := range criticalPath(ctors) {
+		stats.CriticalPath = append(stats.CriticalPath, stats.Constructors[i])
+		stats.CriticalPathTime += stats.Constructors[i].Self
+	}
+	return stats
+}
+
+// criticalPath returns the indexes of the called constructors that form
+// the chain with the largest total self time, starting with the one that
+// depends on all the others.
+func criticalPath(ctors []*constructorNode) []int {
+	index := make(map[*constructorNode]int, len(ctors))
+	for i, n := range ctors {
+		if n.stats.Calls() > 0 {
+			index[n] = i
+		}
+	}
+
+	// longest[i] is the time of the chain starting at ctors[i], length[i]
+	// the number of constructors in it, and next[i] the constructor that
+	// follows ctors[i] in it, if any.
+	var (
+		longest = make([]time.Duration, len(ctors))
+		length  = make([]int, len(ctors))
+		next    = make([]int, len(ctors))
+		state   = make([]int8, len(ctors)) // 0: not visited, 1: visiting, 2: done
+		visit   func(i int)
+	)
+	// longer reports whether the chain starting at ctors[i] should be
+	// preferred to the one starting at ctors[j]. Of chains that take the
+	// same time, the one with the most constructors is preferred.
+	longer := func(i, j int) bool {
+		switch {
+		case j < 0:
+			return true
+		case longest[i] != longest[j]:
+			return longest[i] > longest[j]
+		default:
+			return length[i] > length[j]
+		}
+	}
+	visit = func(i int) {
+		state[i] = 1
+		next[i] = -1
+		n := ctors[i]
+		for _, p := range n.paramList.Params {
+			for _, dep := range paramProviders(n.s, p) {
+				j, ok := index[dep]
+				if !ok {
+					continue
+				}
+				switch state[j] {
+				case 0:
+					visit(j)
+				case 1:
+					// Only possible through constructors that call back
+					// into the Container. Ignore the dependency.
+					continue
+				}
+				if longer(j, next[i]) {
+					next[i] = j
+				}
+			}
+		}
+		longest[i], length[i] = n.stats.Runtime(), 1
+		if next[i] >= 0 {
+			longest[i] += longest[next[i]]
+			length[i] += length[next[i]]
+		}
+		state[i] = 2
+	}
+
+	start := -1
+	for i := range ctors {
+		if _, ok := index[ctors[i]]; !ok {
+			continue
+		}
+		if state[i] == 0 {
+			visit(i)
+		}
+		if longer(i, start) {
+			start = i
+		}
+	}
+
+	var path []int
+	for i := start; i >= 0; i = next[i] {
+		path = append(path, i)
+	}
+	return path
+}
+
+// paramProviders returns the constructors that may be called to build the
+// parameter from the given Scope, before the function that depends on it.
+func paramProviders(s *Scope, param param) []*constructorNode {
+	var providers []provider
+	switch p := param.(type) {
+	case paramSingle:
+		providers = s.getAllProviders(key{name: p.Name, t: p.Type})
+	case paramGroupedSlice:
+		providers = s.getAllProviders(key{group: p.Group, t: p.Type.Elem()})
+	case paramObject:
+		var ctors []*constructorNode
+		for _, f := range p.Fields {
+			ctors = append(ctors, paramProviders(s, f.Param)...)
+		}
+		return ctors
+	case paramLazy:
+		// Lazy values are built after the function that depends on
+		// them.
+	}
+
+	ctors := make([]*constructorNode, 0, len(providers))
+	for _, p := range providers {
+		if n, ok := p.(*constructorNode); ok {
+			ctors = append(ctors, n)
+		}
+	}
+	return ctors
+}
+
+// StatsVar reports the Stats of a Container as JSON. It implements the
+// expvar.Var interface, so it can be published with the expvar package:
+//
+//	expvar.Publish("dig", c.StatsVar())
+type StatsVar struct {
+	c *Container
+}
+
+// StatsVar returns a StatsVar that reports the Stats of the Container at
+// the time it is read.
+func (c *Container) StatsVar() StatsVar {
+	return StatsVar{c: c}
+}
+
+// String returns the current Stats of the Container as JSON.
+func (v StatsVar) String() string {
+	b, err := json.Marshal(v.c.Stats())
+	if err != nil {
+		// Stats only holds values that can always be marshaled.
+		return "null"
+	}
+	return string(b)
+}
@@ -0,0 +1,226 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig_test
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/dig"
+	"go.uber.org/dig/internal/digclock"
+	"go.uber.org/dig/internal/digtest"
+)
+
+func TestStats(t *testing.T) {
+	t.Parallel()
+
+	type A struct{}
+	type B struct{}
+	type C struct{}
+	type D struct{}
+
+	// funcNames returns the names of the functions in stats.
+	funcNames := func(stats []dig.FuncStats) []string {
+		names := make([]string, len(stats))
+		for i, fs := range stats {
+			names[i] = fs.Location.Name
+		}
+		return names
+	}
+
+	t.Run("timing and critical path", func(t *testing.T) {
+		t.Parallel()
+
+		clock := digclock.NewMock()
+		c := digtest.New(t, dig.SetClock(clock))
+		c.RequireProvide(func() A {
+			clock.Add(time.Millisecond)
+			return A{}
+		})
+		c.RequireProvide(func(A) B {
+			clock.Add(2 * time.Millisecond)
+			return B{}
+		})
+		c.RequireProvide(func() C {
+			clock.Add(4 * time.Millisecond)
+			return C{}
+		})
+		c.RequireProvide(func(B, C) D {
+			clock.Add(time.Millisecond)
+			return D{}
+		})
+		c.RequireProvide(func() int { return 42 })
+
+		stats := c.Stats()
+		require.Len(t, stats.Constructors, 5)
+		assert.Empty(t, stats.CriticalPath)
+		assert.Zero(t, stats.CriticalPathTime)
+
+		c.RequireInvoke(func(D) {})
+		stats = c.Stats()
+
+		type timing struct {
+			Calls       int
+			Self, Total time.Duration
+		}
+		timings := make([]timing, len(stats.Constructors))
+		for i, fs := range stats.Constructors {
+			assert.Zero(t, fs.Errors)
+			assert.Zero(t, fs.Panics)
+			assert.Empty(t, fs.Scope)
+			timings[i] = timing{Calls: fs.Calls, Self: fs.Self, Total: fs.Total}
+		}
+		assert.Equal(t, []timing{
+			{Calls: 1, Self: time.Millisecond, Total: time.Millisecond},
+			{Calls: 1, Self: 2 * time.Millisecond, Total: 3 * time.Millisecond},
+			{Calls: 1, Self: 4 * time.Millisecond, Total: 4 * time.Millisecond},
+			{Calls: 1, Self: time.Millisecond, Total: 8 * time.Millisecond},
+			{},
+		}, timings)
+
+		providers := c.Providers()
+		for i, fs := range stats.Constructors {
+			assert.Equal(t, providers[i].ID, fs.ID)
+			assert.Equal(t, providers[i].Location, fs.Location)
+		}
+
+		assert.Equal(t, []string{"TestStats.func2.4", "TestStats.func2.3"}, funcNames(stats.CriticalPath))
+		assert.Equal(t, 5*time.Millisecond, stats.CriticalPathTime)
+	})
+
+	t.Run("groups and parameter objects", func(t *testing.T) {
+		t.Parallel()
+
+		clock := digclock.NewMock()
+		c := digtest.New(t, dig.SetClock(clock))
+		c.RequireProvide(func() A {
+			clock.Add(time.Millisecond)
+			return A{}
+		})
+		c.RequireProvide(func(A) int {
+			clock.Add(3 * time.Millisecond)
+			return 1
+		}, dig.Group("ints"))
+		c.RequireProvide(func() int {
+			clock.Add(2 * time.Millisecond)
+			return 2
+		}, dig.Group("ints"))
+
+		type in struct {
+			dig.In
+
+			A    A
+			Ints []int `group:"ints"`
+		}
+		c.RequireProvide(func(in) B { return B{} })
+		c.RequireInvoke(func(B) {})
+
+		stats := c.Stats()
+		assert.Equal(t, []string{"TestStats.func3.4", "TestStats.func3.2", "TestStats.func3.1"}, funcNames(stats.CriticalPath))
+		assert.Equal(t, 4*time.Millisecond, stats.CriticalPathTime)
+	})
+
+	t.Run("errors and panics", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() (A, error) { return A{}, errors.New("great sadness") })
+		c.RequireProvide(func() B { panic("great sadness") })
+		c.RequireProvide(func(A) C { return C{} })
+
+		assert.Error(t, c.Invoke(func(C) {}))
+		assert.Error(t, c.Invoke(func(C) {}))
+		assert.Panics(t, func() { _ = c.Invoke(func(B) {}) })
+
+		stats := c.Stats()
+		require.Len(t, stats.Constructors, 3)
+		assert.Equal(t, 2, stats.Constructors[0].Calls)
+		assert.Equal(t, 2, stats.Constructors[0].Errors)
+		assert.Equal(t, 1, stats.Constructors[1].Calls)
+		assert.Equal(t, 1, stats.Constructors[1].Panics)
+		assert.Zero(t, stats.Constructors[1].Errors)
+		assert.Zero(t, stats.Constructors[2].Calls, "C must not be called if A fails")
+	})
+
+	t.Run("recovered panics", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t, dig.RecoverFromPanics())
+		c.RequireProvide(func() A { panic("great sadness") })
+		assert.Error(t, c.Invoke(func(A) {}))
+
+		stats := c.Stats()
+		require.Len(t, stats.Constructors, 1)
+		assert.Equal(t, 1, stats.Constructors[0].Panics)
+		assert.Zero(t, stats.Constructors[0].Errors)
+	})
+
+	t.Run("decorators and scopes", func(t *testing.T) {
+		t.Parallel()
+
+		clock := digclock.NewMock()
+		c := digtest.New(t, dig.SetClock(clock))
+		c.RequireProvide(func() A { return A{} })
+
+		child := c.Scope("child")
+		child.RequireProvide(func(A) B { return B{} })
+		child.RequireDecorate(func(a A) A {
+			clock.Add(time.Millisecond)
+			return a
+		})
+		child.RequireInvoke(func(B) {})
+
+		stats := c.Stats()
+		require.Len(t, stats.Constructors, 2)
+		assert.Equal(t, "child", stats.Constructors[1].Scope)
+		require.Len(t, stats.Decorators, 1)
+		assert.Equal(t, c.Decorators()[0].ID, stats.Decorators[0].ID)
+		assert.Equal(t, "child", stats.Decorators[0].Scope)
+		assert.Equal(t, 1, stats.Decorators[0].Calls)
+		assert.Equal(t, time.Millisecond, stats.Decorators[0].Self)
+		assert.Equal(t, time.Millisecond, stats.Decorators[0].Total)
+
+		childStats := child.Stats()
+		require.Len(t, childStats.Constructors, 1)
+		assert.Equal(t, stats.Constructors[1], childStats.Constructors[0])
+		assert.Equal(t, stats.Decorators, childStats.Decorators)
+	})
+}
+
+func TestStatsVar(t *testing.T) {
+	t.Parallel()
+
+	type A struct{}
+
+	c := digtest.New(t)
+	c.RequireProvide(func() A { return A{} })
+	c.RequireInvoke(func(A) {})
+
+	var v expvar.Var = c.StatsVar()
+	var stats dig.Stats
+	require.NoError(t, json.Unmarshal([]byte(v.String()), &stats))
+	assert.Equal(t, c.Stats(), stats)
+}
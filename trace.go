@@ -0,0 +1,356 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/dig/internal/digclock"
+	"go.uber.org/dig/internal/digreflect"
+)
+
+// Tracer records the work done by a Container as a tree of spans: one for
+// each Invoke, nested with one for each constructor or decorator called to
+// build its dependencies. See WithTracer.
+//
+// Spans are timed with the clock of the Container. Use WriteChromeTrace to
+// view them.
+type Tracer struct {
+	mu    sync.Mutex
+	spans []*traceSpan // indexed by ID-1
+}
+
+// NewTracer returns a new Tracer with no spans.
+func NewTracer() *Tracer {
+	return new(Tracer)
+}
+
+// WithTracer is an Option that records the Invokes made on the Container
+// and its Scopes with the given Tracer.
+//
+//	tracer := dig.NewTracer()
+//	c := dig.New(dig.WithTracer(tracer))
+//	// ...
+//	err := tracer.WriteChromeTrace(f)
+func WithTracer(t *Tracer) Option {
+	return withTracerOption{t}
+}
+
+type withTracerOption struct{ t *Tracer }
+
+func (o withTracerOption) String() string {
+	return fmt.Sprintf("WithTracer(%p)", o.t)
+}
+
+func (o withTracerOption) applyOption(c *Container) {
+	c.scope.tracer = o.t
+}
+
+// SpanKind is the kind of work recorded by a Span.
+type SpanKind int
+
+const (
+	// SpanInvoke records a call to Invoke, including building the
+	// dependencies of the invoked function.
+	SpanInvoke SpanKind = iota + 1
+
+	// SpanConstructor records a call to a constructor, including
+	// building its dependencies.
+	SpanConstructor
+
+	// SpanDecorator records a call to a decorator, including building
+	// its dependencies.
+	SpanDecorator
+
+	// SpanWait records the time spent waiting on a constructor or
+	// decorator that was being called by another goroutine.
+	SpanWait
+)
+
+func (k SpanKind) String() string {
+	switch k {
+	case SpanInvoke:
+		return "invoke"
+	case SpanConstructor:
+		return "constructor"
+	case SpanDecorator:
+		return "decorator"
+	case SpanWait:
+		return "wait"
+	default:
+		return fmt.Sprintf("SpanKind(%d)", int(k))
+	}
+}
+
+// Span records an Invoke or a call made to build the dependencies of one.
+type Span struct {
+	// ID of the span. IDs start at 1 and are assigned in the order spans
+	// are started.
+	ID int
+
+	// ID of the span during which this span was started, or 0 for the
+	// spans of Invokes.
+	Parent int
+
+	// Kind of work recorded by the span.
+	Kind SpanKind
+
+	// Location of the invoked function, constructor or decorator.
+	Location Location
+
+	// Name of the Scope the function was called in.
+	Scope string
+
+	// Parameter of the parent span that the call was made for, or nil
+	// for the spans of Invokes.
+	Requested *Input
+
+	// Time at which the span was started, and how long it lasted.
+	Start    time.Time
+	Duration time.Duration
+
+	// Error the call failed with, if any.
+	Err error
+}
+
+// traceSpan is a Span that may still be running.
+type traceSpan struct {
+	Span
+
+	tracer  *Tracer
+	clock   digclock.Clock
+	running bool
+}
+
+// Spans returns the spans that ended so far, in the order they were
+// started.
+func (t *Tracer) Spans() []Span {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	spans := make([]Span, 0, len(t.spans))
+	for _, sp := range t.spans {
+		if !sp.running {
+			spans = append(spans, sp.Span)
+		}
+	}
+	return spans
+}
+
+// start starts a span for a call made on the path of bs.
+func (t *Tracer) start(bs *buildState, kind SpanKind, f *digreflect.Func, scope string, clock digclock.Clock) *traceSpan {
+	sp := &traceSpan{
+		Span: Span{
+			Kind:     kind,
+			Location: newLocation(f),
+			Scope:    scope,
+		},
+		tracer:  t,
+		clock:   clock,
+		running: true,
+	}
+	if bs != nil {
+		if bs.span != nil {
+			sp.Parent = bs.span.ID
+		}
+		sp.Requested = bs.requested
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	sp.ID = len(t.spans) + 1
+	sp.Start = clock.Now()
+	t.spans = append(t.spans, sp)
+	return sp
+}
+
+// end ends the span with the outcome of its call. It's a no-op on a nil
+// span.
+func (sp *traceSpan) end(err error) {
+	if sp == nil {
+		return
+	}
+
+	t := sp.tracer
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	sp.Duration = sp.clock.Since(sp.Start)
+	sp.Err = err
+	sp.running = false
+}
+
+// startSpan starts a span for a call made on the path of bs if the Scope
+// has a Tracer. It returns nil otherwise.
+func (s *Scope) startSpan(bs *buildState, kind SpanKind, f *digreflect.Func) *traceSpan {
+	if s.tracer == nil {
+		return nil
+	}
+	return s.tracer.start(bs, kind, f, s.name, s.clock())
+}
+
+// chromeTrace is a trace in the Chrome Trace Event Format, as understood
+// by chrome://tracing and Perfetto. See
+// https://docs.google.com/document/d/1CvAClvFfyA5R-PhYUmn5OOQtYMH4h6I0nSsKchNAySU.
+type chromeTrace struct {
+	TraceEvents []chromeTraceEvent `json:"traceEvents"`
+}
+
+// chromeTraceEvent is a complete event ("ph": "X") of a Chrome trace.
+// Timestamps and durations are in microseconds.
+type chromeTraceEvent struct {
+	Name     string          `json:"name"`
+	Category string          `json:"cat"`
+	Phase    string          `json:"ph"`
+	TS       float64         `json:"ts"`
+	Dur      float64         `json:"dur"`
+	PID      int             `json:"pid"`
+	TID      int             `json:"tid"`
+	Args     chromeTraceArgs `json:"args"`
+}
+
+type chromeTraceArgs struct {
+	ID        int    `json:"id"`
+	Parent    int    `json:"parent,omitempty"`
+	Location  string `json:"location"`
+	Scope     string `json:"scope,omitempty"`
+	Requested string `json:"requested,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// WriteChromeTrace writes the spans that ended so far to w in the Chrome
+// Trace Event Format. The trace can be viewed in chrome://tracing or
+// https://ui.perfetto.dev.
+//
+// Timestamps are relative to the start of the first span. Spans are drawn
+// nested in their parent span, except for spans that ran concurrently with
+// their siblings, as they do with ParallelConstruction. Those are moved to
+// separate threads of the trace.
+func (t *Tracer) WriteChromeTrace(w io.Writer) error {
+	spans := t.Spans()
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].Start.Before(spans[j].Start)
+	})
+	lanes := traceLanes(spans)
+
+	trace := chromeTrace{TraceEvents: make([]chromeTraceEvent, len(spans))}
+	for i, sp := range spans {
+		name := sp.Location.Package + "." + sp.Location.Name
+		if sp.Kind == SpanWait {
+			name = "waiting for " + name
+		}
+		args := chromeTraceArgs{
+			ID:       sp.ID,
+			Parent:   sp.Parent,
+			Location: fmt.Sprintf("%v:%v", sp.Location.File, sp.Location.Line),
+			Scope:    sp.Scope,
+		}
+		if sp.Requested != nil {
+			args.Requested = sp.Requested.String()
+		}
+		if sp.Err != nil {
+			args.Error = sp.Err.Error()
+		}
+		trace.TraceEvents[i] = chromeTraceEvent{
+			Name:     name,
+			Category: sp.Kind.String(),
+			Phase:    "X",
+			TS:       microseconds(sp.Start.Sub(spans[0].Start)),
+			Dur:      microseconds(sp.Duration),
+			PID:      1,
+			TID:      lanes[i] + 1,
+			Args:     args,
+		}
+	}
+	return json.NewEncoder(w).Encode(trace)
+}
+
+func microseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Microsecond)
+}
+
+// traceLanes assigns spans, sorted by start time, to lanes such that the
+// spans of a lane nest properly. Spans are put in the lane of their parent
+// unless another span is still running there.
+func traceLanes(spans []Span) []int {
+	index := make(map[int]int, len(spans)) // span ID -> index
+	for i, sp := range spans {
+		index[sp.ID] = i
+	}
+	isAncestor := func(a, i int) bool {
+		for p := spans[i].Parent; p != 0; p = spans[index[p]].Parent {
+			if _, ok := index[p]; !ok {
+				return false
+			}
+			if index[p] == a {
+				return true
+			}
+		}
+		return false
+	}
+
+	var (
+		lanes  = make([]int, len(spans))
+		stacks [][]int // spans that may still run, per lane
+	)
+	// fits reports whether span i can be put in the given lane, dropping
+	// the spans of the lane that ended before it started.
+	fits := func(lane, i int) bool {
+		stack := stacks[lane]
+		for len(stack) > 0 {
+			top := stack[len(stack)-1]
+			if isAncestor(top, i) {
+				break
+			}
+			if spans[top].Start.Add(spans[top].Duration).After(spans[i].Start) {
+				return false
+			}
+			stack = stack[:len(stack)-1]
+		}
+		stacks[lane] = stack
+		return true
+	}
+
+	for i, sp := range spans {
+		lane := -1
+		if p, ok := index[sp.Parent]; ok && fits(lanes[p], i) {
+			lane = lanes[p]
+		}
+		for l := 0; lane < 0 && l < len(stacks); l++ {
+			if fits(l, i) {
+				lane = l
+			}
+		}
+		if lane < 0 {
+			lane = len(stacks)
+			stacks = append(stacks, nil)
+		}
+		lanes[i] = lane
+		stacks[lane] = append(stacks[lane], i)
+	}
+	return lanes
+}
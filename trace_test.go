@@ -0,0 +1,304 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/dig"
+	"go.uber.org/dig/internal/digclock"
+	"go.uber.org/dig/internal/digtest"
+)
+
+// spanSummary is the part of a Span that doesn't depend on where the test
+// is defined.
+type spanSummary struct {
+	ID, Parent int
+	Kind       dig.SpanKind
+	Requested  string
+	Start      time.Duration
+	Duration   time.Duration
+}
+
+func summarizeSpans(spans []dig.Span) []spanSummary {
+	out := make([]spanSummary, len(spans))
+	for i, sp := range spans {
+		s := spanSummary{
+			ID:       sp.ID,
+			Parent:   sp.Parent,
+			Kind:     sp.Kind,
+			Start:    sp.Start.Sub(spans[0].Start),
+			Duration: sp.Duration,
+		}
+		if sp.Requested != nil {
+			s.Requested = sp.Requested.String()
+		}
+		out[i] = s
+	}
+	return out
+}
+
+func TestTracer(t *testing.T) {
+	t.Parallel()
+
+	type A struct{}
+	type B struct{}
+	type C struct{}
+
+	t.Run("nested spans", func(t *testing.T) {
+		t.Parallel()
+
+		clock := digclock.NewMock()
+		tracer := dig.NewTracer()
+		c := digtest.New(t, dig.SetClock(clock), dig.WithTracer(tracer))
+
+		c.RequireProvide(func() A {
+			clock.Add(time.Millisecond)
+			return A{}
+		})
+		c.RequireProvide(func(A) B {
+			clock.Add(2 * time.Millisecond)
+			return B{}
+		}, dig.Name("b"))
+		c.RequireProvide(func() C {
+			clock.Add(3 * time.Millisecond)
+			return C{}
+		}, dig.Group("cs"))
+		c.RequireDecorate(func(a A) A {
+			clock.Add(4 * time.Millisecond)
+			return a
+		})
+
+		type in struct {
+			dig.In
+
+			B  B   `name:"b"`
+			Cs []C `group:"cs"`
+		}
+		c.RequireInvoke(func(in) {
+			clock.Add(5 * time.Millisecond)
+		})
+
+		spans := tracer.Spans()
+		assert.Equal(t, []spanSummary{
+			{ID: 1, Kind: dig.SpanInvoke, Duration: 15 * time.Millisecond},
+			{ID: 2, Parent: 1, Kind: dig.SpanConstructor, Requested: `dig_test.B[name = "b"]`, Duration: 7 * time.Millisecond},
+			{ID: 3, Parent: 2, Kind: dig.SpanDecorator, Requested: "dig_test.A", Duration: 5 * time.Millisecond},
+			{ID: 4, Parent: 3, Kind: dig.SpanConstructor, Requested: "dig_test.A", Duration: time.Millisecond},
+			{ID: 5, Parent: 1, Kind: dig.SpanConstructor, Requested: `[]dig_test.C[group = "cs"]`, Start: 7 * time.Millisecond, Duration: 3 * time.Millisecond},
+		}, summarizeSpans(spans))
+
+		assert.Equal(t, "TestTracer.func1.5", spans[0].Location.Name)
+		assert.Equal(t, "go.uber.org/dig_test", spans[0].Location.Package)
+		assert.Equal(t, "TestTracer.func1.4", spans[2].Location.Name)
+		for _, sp := range spans {
+			assert.Empty(t, sp.Scope)
+			assert.NoError(t, sp.Err)
+		}
+
+		// Values that were already built aren't traced again.
+		c.RequireInvoke(func(A) {})
+		spans = tracer.Spans()
+		require.Len(t, spans, 6)
+		assert.Equal(t, dig.SpanInvoke, spans[5].Kind)
+	})
+
+	t.Run("scopes", func(t *testing.T) {
+		t.Parallel()
+
+		tracer := dig.NewTracer()
+		c := digtest.New(t, dig.WithTracer(tracer))
+		c.RequireProvide(func() A { return A{} })
+
+		child := c.Scope("child")
+		child.RequireProvide(func(A) B { return B{} })
+		child.RequireInvoke(func(B) {})
+
+		spans := tracer.Spans()
+		require.Len(t, spans, 3)
+		assert.Equal(t, "child", spans[0].Scope)
+		assert.Equal(t, "child", spans[1].Scope)
+		assert.Empty(t, spans[2].Scope, "A is built in the Scope it was provided to")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		tracer := dig.NewTracer()
+		c := digtest.New(t, dig.WithTracer(tracer))
+		giveErr := errors.New("great sadness")
+		c.RequireProvide(func() (A, error) { return A{}, giveErr })
+		c.RequireProvide(func(A) B { return B{} })
+
+		err := c.Invoke(func(B) {})
+		require.Error(t, err)
+
+		spans := tracer.Spans()
+		require.Len(t, spans, 3)
+		assert.ErrorIs(t, spans[0].Err, giveErr)
+		assert.ErrorIs(t, spans[1].Err, giveErr)
+		assert.ErrorIs(t, spans[2].Err, giveErr)
+	})
+
+	t.Run("parallel construction", func(t *testing.T) {
+		t.Parallel()
+
+		tracer := dig.NewTracer()
+		c := digtest.New(t, dig.ParallelConstruction(2), dig.WithTracer(tracer))
+
+		// A and B are only built once both of them started, so they
+		// always run at the same time.
+		started := make(chan struct{}, 2)
+		wait := func() {
+			started <- struct{}{}
+			for len(started) < 2 {
+				time.Sleep(time.Millisecond)
+			}
+			time.Sleep(time.Millisecond)
+		}
+		c.RequireProvide(func() A { wait(); return A{} })
+		c.RequireProvide(func() B { wait(); return B{} })
+		c.RequireInvoke(func(A, B) {})
+
+		var buf bytes.Buffer
+		require.NoError(t, tracer.WriteChromeTrace(&buf))
+		var trace struct {
+			TraceEvents []struct {
+				TID  int `json:"tid"`
+				Args struct {
+					ID int `json:"id"`
+				} `json:"args"`
+			} `json:"traceEvents"`
+		}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &trace))
+		require.Len(t, trace.TraceEvents, 3)
+
+		tids := make(map[int]int) // span ID -> thread
+		for _, e := range trace.TraceEvents {
+			tids[e.Args.ID] = e.TID
+		}
+		assert.Equal(t, 1, tids[1], "invoke")
+		assert.NotEqual(t, tids[2], tids[3], "concurrent constructors must be on separate threads")
+	})
+}
+
+func TestTracerWriteChromeTrace(t *testing.T) {
+	t.Parallel()
+
+	type A struct{}
+	type B struct{}
+
+	clock := digclock.NewMock()
+	tracer := dig.NewTracer()
+	c := digtest.New(t, dig.SetClock(clock), dig.WithTracer(tracer))
+	c.RequireProvide(func() A {
+		clock.Add(1500 * time.Microsecond)
+		return A{}
+	})
+	c.RequireProvide(func(A) (B, error) {
+		clock.Add(time.Millisecond)
+		return B{}, errors.New("great sadness")
+	})
+	c.RequireInvoke(func(A) {})
+	clock.Add(time.Second)
+	require.Error(t, c.Invoke(func(B) {}))
+
+	var buf bytes.Buffer
+	require.NoError(t, tracer.WriteChromeTrace(&buf))
+
+	type args struct {
+		ID        int    `json:"id"`
+		Parent    int    `json:"parent"`
+		Location  string `json:"location"`
+		Requested string `json:"requested"`
+		Error     string `json:"error"`
+	}
+	type event struct {
+		Name string  `json:"name"`
+		Cat  string  `json:"cat"`
+		Ph   string  `json:"ph"`
+		TS   float64 `json:"ts"`
+		Dur  float64 `json:"dur"`
+		PID  int     `json:"pid"`
+		TID  int     `json:"tid"`
+		Args args    `json:"args"`
+	}
+	var trace struct {
+		TraceEvents []event `json:"traceEvents"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &trace))
+	events := trace.TraceEvents
+	require.Len(t, events, 4)
+
+	for _, e := range events {
+		assert.Equal(t, "X", e.Ph)
+		assert.Equal(t, 1, e.PID)
+		assert.Equal(t, 1, e.TID)
+		assert.Contains(t, e.Args.Location, "trace_test.go:")
+	}
+
+	assert.Equal(t, "go.uber.org/dig_test.TestTracerWriteChromeTrace.func3", events[0].Name)
+	assert.Equal(t, "go.uber.org/dig_test.TestTracerWriteChromeTrace.func1", events[1].Name)
+
+	summary := make([]event, len(events))
+	for i, e := range events {
+		e.Name = ""
+		e.Args.Location = ""
+		summary[i] = e
+	}
+	invokeErr, ctorErr := events[2].Args.Error, events[3].Args.Error
+	assert.Contains(t, invokeErr, "great sadness")
+	assert.Contains(t, ctorErr, "great sadness")
+	assert.Equal(t, []event{
+		{Cat: "invoke", Ph: "X", TS: 0, Dur: 1500, PID: 1, TID: 1, Args: args{ID: 1}},
+		{Cat: "constructor", Ph: "X", TS: 0, Dur: 1500, PID: 1, TID: 1, Args: args{ID: 2, Parent: 1, Requested: "dig_test.A"}},
+		{Cat: "invoke", Ph: "X", TS: 1001500, Dur: 1000, PID: 1, TID: 1, Args: args{ID: 3, Error: invokeErr}},
+		{Cat: "constructor", Ph: "X", TS: 1001500, Dur: 1000, PID: 1, TID: 1, Args: args{ID: 4, Parent: 3, Requested: "dig_test.B", Error: ctorErr}},
+	}, summary)
+}
+
+func TestTracerEmpty(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, dig.NewTracer().WriteChromeTrace(&buf))
+	assert.JSONEq(t, `{"traceEvents": []}`, buf.String())
+}
+
+func TestTracerStrings(t *testing.T) {
+	t.Parallel()
+
+	tracer := dig.NewTracer()
+	assert.Regexp(t, `^WithTracer\(0x[0-9a-f]+\)$`, fmt.Sprint(dig.WithTracer(tracer)))
+
+	assert.Equal(t, "invoke", dig.SpanInvoke.String())
+	assert.Equal(t, "constructor", dig.SpanConstructor.String())
+	assert.Equal(t, "decorator", dig.SpanDecorator.String())
+	assert.Equal(t, "wait", dig.SpanWait.String())
+	assert.Equal(t, "SpanKind(42)", dig.SpanKind(42).String())
+}
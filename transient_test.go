@@ -0,0 +1,250 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/dig"
+	"go.uber.org/dig/internal/digtest"
+)
+
+func TestTransient(t *testing.T) {
+	t.Parallel()
+
+	t.Run("new value for every parameter", func(t *testing.T) {
+		t.Parallel()
+
+		type params struct {
+			dig.In
+
+			A *bytes.Buffer
+			B *bytes.Buffer
+		}
+
+		var calls int
+		c := digtest.New(t)
+		c.RequireProvide(func() *bytes.Buffer {
+			calls++
+			return new(bytes.Buffer)
+		}, dig.Transient())
+
+		c.RequireInvoke(func(a, b *bytes.Buffer, p params) {
+			assert.NotSame(t, a, b)
+			assert.NotSame(t, p.A, p.B)
+			assert.NotSame(t, a, p.A)
+		})
+		assert.Equal(t, 4, calls)
+
+		c.RequireInvoke(func(*bytes.Buffer) {})
+		assert.Equal(t, 5, calls)
+	})
+
+	t.Run("dependencies are still singletons", func(t *testing.T) {
+		t.Parallel()
+
+		type Config struct{}
+		type Client struct{ cfg *Config }
+
+		var cfgCalls int
+		c := digtest.New(t)
+		c.RequireProvide(func() *Config {
+			cfgCalls++
+			return &Config{}
+		})
+		c.RequireProvide(func(cfg *Config) *Client {
+			return &Client{cfg: cfg}
+		}, dig.Transient())
+
+		c.RequireInvoke(func(a, b *Client) {
+			assert.NotSame(t, a, b)
+			assert.Same(t, a.cfg, b.cfg)
+		})
+		assert.Equal(t, 1, cfgCalls)
+	})
+
+	t.Run("singleton depending on transient", func(t *testing.T) {
+		t.Parallel()
+
+		type Service struct{ buf *bytes.Buffer }
+
+		var calls int
+		c := digtest.New(t)
+		c.RequireProvide(func() *bytes.Buffer {
+			calls++
+			return new(bytes.Buffer)
+		}, dig.Transient())
+		c.RequireProvide(func(buf *bytes.Buffer) *Service {
+			return &Service{buf: buf}
+		})
+
+		var first *Service
+		c.RequireInvoke(func(s *Service) { first = s })
+		c.RequireInvoke(func(s *Service) { assert.Same(t, first, s) })
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("result objects", func(t *testing.T) {
+		t.Parallel()
+
+		type result struct {
+			dig.Out
+
+			Buffer *bytes.Buffer
+			Name   string `name:"id"`
+		}
+
+		type params struct {
+			dig.In
+
+			Name string `name:"id"`
+		}
+
+		var calls int
+		c := digtest.New(t)
+		c.RequireProvide(func() result {
+			calls++
+			return result{Buffer: new(bytes.Buffer), Name: "id"}
+		}, dig.Transient())
+
+		c.RequireInvoke(func(_ *bytes.Buffer, p params) {
+			assert.Equal(t, "id", p.Name)
+		})
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("value groups", func(t *testing.T) {
+		t.Parallel()
+
+		type params struct {
+			dig.In
+
+			Values []int `group:"values"`
+			Soft   []int `group:"values,soft"`
+		}
+
+		var next int
+		c := digtest.New(t)
+		c.RequireProvide(func() int {
+			next++
+			return next
+		}, dig.Group("values"), dig.Transient())
+		c.RequireProvide(func() int { return 100 }, dig.Group("values"))
+
+		c.RequireInvoke(func(p params) {
+			assert.ElementsMatch(t, []int{1, 100}, p.Values)
+			assert.Equal(t, []int{100}, p.Soft, "transient values must not be in soft groups")
+		})
+		c.RequireInvoke(func(p params) {
+			assert.ElementsMatch(t, []int{2, 100}, p.Values)
+		})
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		var fail = true
+		c := digtest.New(t)
+		c.RequireProvide(func() (*bytes.Buffer, error) {
+			if fail {
+				return nil, errors.New("great sadness")
+			}
+			return new(bytes.Buffer), nil
+		}, dig.Transient())
+
+		err := c.Invoke(func(*bytes.Buffer) {})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "great sadness")
+
+		fail = false
+		c.RequireInvoke(func(*bytes.Buffer) {})
+	})
+
+	t.Run("scopes", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int
+		c := digtest.New(t)
+		c.RequireProvide(func() *bytes.Buffer {
+			calls++
+			return new(bytes.Buffer)
+		}, dig.Transient())
+
+		s := c.Scope("child")
+		s.RequireInvoke(func(*bytes.Buffer) {})
+		c.RequireInvoke(func(*bytes.Buffer) {})
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("cleanups run for every value", func(t *testing.T) {
+		t.Parallel()
+
+		var closed int
+		c := digtest.New(t)
+		c.RequireProvide(func() (*bytes.Buffer, func()) {
+			return new(bytes.Buffer), func() { closed++ }
+		}, dig.Transient(), dig.Cleanup())
+
+		c.RequireInvoke(func(_, _ *bytes.Buffer) {})
+		require.NoError(t, c.Close())
+		assert.Equal(t, 2, closed)
+	})
+
+	t.Run("cannot decorate transient values", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() *bytes.Buffer { return new(bytes.Buffer) }, dig.Transient())
+
+		err := c.Scope("child").Decorate(func(b *bytes.Buffer) *bytes.Buffer { return b })
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "*bytes.Buffer is provided by a transient constructor")
+	})
+
+	t.Run("cannot provide decorated values as transient", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireDecorate(func(b *bytes.Buffer) *bytes.Buffer { return b })
+
+		err := c.Provide(func() *bytes.Buffer { return new(bytes.Buffer) }, dig.Transient())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot provide transient *bytes.Buffer: it is already decorated")
+	})
+
+	t.Run("cycles are detected", func(t *testing.T) {
+		t.Parallel()
+
+		type A struct{}
+		type B struct{}
+
+		c := digtest.New(t)
+		c.RequireProvide(func(*B) *A { return &A{} }, dig.Transient())
+
+		err := c.Provide(func(*A) *B { return &B{} }, dig.Transient())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "this function introduces a cycle")
+	})
+}
@@ -0,0 +1,427 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+
+	"go.uber.org/dig/internal/digreflect"
+)
+
+// Verify checks that the dependencies of the given functions can be
+// satisfied by the Container without calling any constructors.
+//
+// Unlike Invoke, which only checks the direct dependencies of the function
+// before building them, Verify walks every constructor that the functions
+// depend on, directly or transitively. It reports all the types that are
+// missing and all the dependency cycles it finds in a single error, along
+// with the path of constructors that leads to each of them.
+//
+//	if err := c.Verify(func(*http.Server) {}); err != nil {
+//	  log.Fatalf("invalid dependency graph: %+v", err)
+//	}
+//
+// If no functions are given, Verify checks the dependencies of all the
+// constructors and decorators of the Container and its Scopes.
+//
+// Dependencies that are only used by optional parameters may be missing.
+// Values that were already built are not taken into account.
+func (c *Container) Verify(roots ...interface{}) error {
+	return c.scope.Verify(roots...)
+}
+
+// Verify checks that the dependencies of the given functions can be
+// satisfied by the Scope without calling any constructors. See
+// Container.Verify for details.
+//
+// If no functions are given, Verify checks the dependencies of all the
+// constructors and decorators of the Scope and its descendants.
+func (s *Scope) Verify(roots ...interface{}) error {
+	for _, root := range roots {
+		rtype := reflect.TypeOf(root)
+		if rtype == nil {
+			return newErrInvalidInput("can't verify an untyped nil", nil)
+		}
+		if rtype.Kind() != reflect.Func {
+			return newErrInvalidInput(
+				fmt.Sprintf("can't verify non-function %v (type %v)", root, rtype), nil)
+		}
+	}
+
+	v := newVerifier()
+	if err := s.verify(v, roots); err != nil {
+		return err
+	}
+	return v.Err()
+}
+
+func (s *Scope) verify(v *verifier, roots []interface{}) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, root := range roots {
+		pl, err := newParamList(reflect.TypeOf(root), s)
+		if err != nil {
+			return err
+		}
+
+		v.push(verifyFrame{Func: digreflect.InspectFunc(root)})
+		v.walkParams(s, pl.Params, false /* optional */)
+		v.pop()
+	}
+
+	if len(roots) == 0 {
+		for _, sc := range s.appendSubscopes(nil) {
+			for _, n := range sc.nodes {
+				ctx := n.OrigScope()
+				if n.PerScope() {
+					ctx = sc
+				}
+				v.visitCtor(ctx, key{t: n.CType()}, n, false /* optional */)
+			}
+			for _, k := range sortedKeys(sc.decorators) {
+				v.visitDecorator(sc, sc.decorators[k], false /* optional */)
+			}
+		}
+	}
+	return nil
+}
+
+// verifyFrame is a function on the dependency path being verified.
+type verifyFrame struct {
+	// Key is the value that the function was visited for. It is unset
+	// for functions passed to Verify.
+	Key   key
+	Func  *digreflect.Func
+	Scope *Scope
+
+	node interface{}
+}
+
+// verifyVisit identifies a function that was verified for a Scope.
+type verifyVisit struct {
+	node     interface{}
+	scope    *Scope
+	optional bool
+}
+
+// verifier walks the dependency graph without building anything and
+// records the problems it finds along the way.
+//
+// The caller must hold the lock of the Scopes being verified.
+type verifier struct {
+	// Functions on the path being walked, with the root first.
+	stack []verifyFrame
+
+	// Position of the nodes on the stack.
+	onStack map[interface{}]int
+
+	// Functions that were verified or are being verified.
+	visited map[verifyVisit]struct{}
+
+	missing      []missingVerifyDependency
+	missingKeys  map[missingVerifyKey]struct{}
+	cycles       []errCycleDetected
+	cyclesByNode map[interface{}]struct{}
+}
+
+// missingVerifyDependency is a missing type found by the verifier.
+// Suggestions are looked up once the walk is over.
+type missingVerifyDependency struct {
+	Key   key
+	Scope *Scope
+	Path  []verifyFrame
+}
+
+// missingVerifyKey identifies a missing type reported for one of the
+// functions the walk started from. The same type may be missing on the
+// paths of several of them.
+type missingVerifyKey struct {
+	key  key
+	root interface{}
+}
+
+func newVerifier() *verifier {
+	return &verifier{
+		onStack:      make(map[interface{}]int),
+		visited:      make(map[verifyVisit]struct{}),
+		missingKeys:  make(map[missingVerifyKey]struct{}),
+		cyclesByNode: make(map[interface{}]struct{}),
+	}
+}
+
+func (v *verifier) push(f verifyFrame) {
+	if f.node != nil {
+		v.onStack[f.node] = len(v.stack)
+	}
+	v.stack = append(v.stack, f)
+}
+
+func (v *verifier) pop() {
+	f := v.stack[len(v.stack)-1]
+	v.stack = v.stack[:len(v.stack)-1]
+	if f.node != nil {
+		delete(v.onStack, f.node)
+	}
+}
+
+func (v *verifier) walkParams(s *Scope, params []param, optional bool) {
+	for _, p := range params {
+		v.walkParam(s, p, optional)
+	}
+}
+
+func (v *verifier) walkParam(s *Scope, p param, optional bool) {
+	switch p := p.(type) {
+	case paramSingle:
+		v.walkSingle(s, p, optional || p.Optional)
+	case paramObject:
+		for _, f := range p.Fields {
+			v.walkParam(s, f.Param, optional)
+		}
+	case paramGroupedSlice:
+		v.walkGroup(s, p, optional)
+	case paramLazy:
+		// Lazy values are built after the function that depends on them,
+		// so they can't be part of a cycle with it.
+		onStack := v.onStack
+		v.onStack = make(map[interface{}]int)
+		v.walkParam(s, p.Value, optional)
+		v.onStack = onStack
+	}
+}
+
+// walkSingle follows the same steps as paramSingle.Build.
+func (v *verifier) walkSingle(s *Scope, p paramSingle, optional bool) {
+	k := key{name: p.Name, t: p.Type}
+	for _, sc := range s.ancestors() {
+		d, ok := sc.decorators[k]
+		if !ok {
+			continue
+		}
+		if _, running := v.onStack[d]; running {
+			continue
+		}
+		v.visitDecorator(sc, d, optional)
+		return
+	}
+
+	for _, sc := range s.ancestors() {
+		providers := sc.providers[k]
+		if len(providers) == 0 {
+			continue
+		}
+		for _, n := range providers {
+			ctx := n.OrigScope()
+			if n.PerScope() {
+				ctx = s
+			}
+			v.visitCtor(ctx, k, n, optional)
+		}
+		return
+	}
+
+	// Unnamed contexts that weren't provided are the context of the
+	// Invoke.
+	if !optional && !p.isContext() {
+		v.addMissing(s, k)
+	}
+}
+
+// walkGroup follows the same steps as paramGroupedSlice.Build.
+func (v *verifier) walkGroup(s *Scope, p paramGroupedSlice, optional bool) {
+	k := key{group: p.Group, t: p.Type.Elem()}
+	scopes := s.ancestors()
+	for i := len(scopes) - 1; i >= 0; i-- {
+		sc := scopes[i]
+		if d, ok := sc.decorators[k]; ok {
+			if _, running := v.onStack[d]; !running {
+				v.visitDecorator(sc, d, optional)
+			}
+		}
+	}
+
+	if p.Soft {
+		// Soft value groups never call their providers.
+		return
+	}
+	for _, sc := range scopes {
+		for _, n := range sc.providers[k] {
+			v.visitCtor(n.OrigScope(), k, n, optional)
+		}
+	}
+}
+
+func (v *verifier) visitCtor(s *Scope, k key, n *constructorNode, optional bool) {
+	if i, ok := v.onStack[n]; ok {
+		v.addCycle(i, k, n)
+		return
+	}
+
+	visit := verifyVisit{node: n, scope: s, optional: optional}
+	if _, ok := v.visited[visit]; ok {
+		return
+	}
+	v.visited[visit] = struct{}{}
+
+	v.push(verifyFrame{Key: k, Func: n.Location(), Scope: s, node: n})
+	v.walkParams(s, n.ParamList().Params, optional)
+	v.pop()
+}
+
+func (v *verifier) visitDecorator(s *Scope, d *decoratorNode, optional bool) {
+	visit := verifyVisit{node: d, scope: s, optional: optional}
+	if _, ok := v.visited[visit]; ok {
+		return
+	}
+	v.visited[visit] = struct{}{}
+
+	k := key{t: d.dtype}
+	if len(d.keys) > 0 {
+		k = d.keys[0]
+	}
+	v.push(verifyFrame{Key: k, Func: d.location, Scope: s, node: d})
+	v.walkParams(s, d.params.Params, optional)
+	v.pop()
+}
+
+func (v *verifier) addMissing(s *Scope, k key) {
+	mk := missingVerifyKey{key: k}
+	if len(v.stack) > 0 {
+		// Functions passed to Verify have no node.
+		if root := v.stack[0]; root.node != nil {
+			mk.root = root.node
+		} else {
+			mk.root = root.Func
+		}
+	}
+	if _, ok := v.missingKeys[mk]; ok {
+		return
+	}
+	v.missingKeys[mk] = struct{}{}
+	v.missing = append(v.missing, missingVerifyDependency{
+		Key:   k,
+		Scope: s,
+		Path:  append([]verifyFrame(nil), v.stack...),
+	})
+}
+
+// addCycle records the cycle that starts at position i of the stack and
+// goes back to it through the value k of n.
+func (v *verifier) addCycle(i int, k key, n interface{}) {
+	if _, ok := v.cyclesByNode[n]; ok {
+		return
+	}
+	v.cyclesByNode[n] = struct{}{}
+
+	frames := v.stack[i:]
+	path := make([]cycleErrPathEntry, 0, len(frames)+1)
+	for _, f := range frames {
+		path = append(path, cycleErrPathEntry{Key: f.Key, Func: f.Func})
+	}
+	path = append(path, cycleErrPathEntry{Key: k, Func: frames[0].Func})
+	v.cycles = append(v.cycles, errCycleDetected{Path: path, scope: frames[0].Scope})
+}
+
+// Err reports the problems found by the verifier, if any. It must be
+// called without holding the lock of the verified Scopes.
+func (v *verifier) Err() error {
+	var errs []error
+	for _, m := range v.missing {
+		errs = append(errs, errMissingDependencyPath{
+			Path:   m.Path,
+			Reason: newErrMissingTypes(m.Scope, m.Key),
+		})
+	}
+	for _, c := range v.cycles {
+		errs = append(errs, c)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errVerifyFailed{Errors: errs}
+}
+
+// errMissingDependencyPath is returned by Verify for a type that is missing
+// from the container, along with the path of functions that depend on it.
+type errMissingDependencyPath struct {
+	Path   []verifyFrame
+	Reason error
+}
+
+var _ digError = errMissingDependencyPath{}
+
+func (e errMissingDependencyPath) Error() string { return fmt.Sprint(e) }
+
+func (e errMissingDependencyPath) Unwrap() error { return e.Reason }
+
+func (e errMissingDependencyPath) writeMessage(w io.Writer, _ string) {
+	// We get something like,
+	//
+	//   "path/to/package".main.func1 (path/to/file.go:42)
+	//   	depends on *foo provided by "path/to/package".NewFoo (path/to/file.go:10)
+	//   	depends on *bar provided by "path/to/package".NewBar (path/to/file.go:20)
+	//
+	// The missing type is reported by the wrapped error.
+	for i, f := range e.Path {
+		if i > 0 {
+			io.WriteString(w, "\n\tdepends on ")
+		}
+		if i == 0 {
+			fmt.Fprint(w, f.Func)
+		} else {
+			fmt.Fprintf(w, "%v provided by %v", f.Key, f.Func)
+		}
+	}
+}
+
+func (e errMissingDependencyPath) Format(w fmt.State, c rune) {
+	formatError(e, w, c)
+}
+
+// errVerifyFailed is returned by Verify with all the problems it found in
+// the dependency graph.
+type errVerifyFailed struct {
+	Errors []error // inv: len > 0
+}
+
+var _ digError = errVerifyFailed{}
+
+func (e errVerifyFailed) Error() string { return fmt.Sprint(e) }
+
+func (e errVerifyFailed) Unwrap() []error { return e.Errors }
+
+func (e errVerifyFailed) writeMessage(w io.Writer, verb string) {
+	if len(e.Errors) == 1 {
+		io.WriteString(w, "found 1 problem in the dependency graph:")
+	} else {
+		fmt.Fprintf(w, "found %d problems in the dependency graph:", len(e.Errors))
+	}
+
+	writeErrorList(w, verb, e.Errors)
+}
+
+func (e errVerifyFailed) Format(w fmt.State, c rune) {
+	formatError(e, w, c)
+}
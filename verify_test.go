@@ -0,0 +1,267 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/dig"
+	"go.uber.org/dig/internal/digtest"
+)
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	type A struct{}
+	type B struct{}
+	type C struct{}
+	type D struct{}
+
+	t.Run("valid graph", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() *A { t.Fatal("must not be called"); return nil })
+		c.RequireProvide(func(*A) *B { t.Fatal("must not be called"); return nil })
+
+		assert.NoError(t, c.Verify(func(*B) {}))
+		assert.NoError(t, c.Verify())
+	})
+
+	t.Run("invalid roots", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		err := c.Verify(nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "can't verify an untyped nil")
+
+		err = c.Verify(42)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "can't verify non-function 42 (type int)")
+	})
+
+	t.Run("deep missing types", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func(*A, *B) *C { return nil })
+		c.RequireProvide(func(*C) *D { return nil })
+
+		// Invoke only finds out about the first missing type once it
+		// starts building.
+		err := c.Verify(func(*D) {})
+		require.Error(t, err)
+
+		msg := err.Error()
+		assert.Contains(t, msg, "found 2 problems in the dependency graph:")
+		assert.Contains(t, msg, "missing type: *dig_test.A")
+		assert.Contains(t, msg, "missing type: *dig_test.B")
+		assert.Contains(t, msg, "depends on *dig_test.D provided by")
+		assert.Contains(t, msg, "depends on *dig_test.C provided by")
+	})
+
+	t.Run("missing type on several paths", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func(*A) *B { return nil })
+		c.RequireProvide(func(*A) *C { return nil })
+
+		err := c.Verify(func(*B) {}, func(*C) {})
+		require.Error(t, err)
+
+		msg := err.Error()
+		assert.Contains(t, msg, "found 2 problems in the dependency graph:")
+		assert.Equal(t, 2, strings.Count(msg, "missing type: *dig_test.A"))
+		assert.Contains(t, msg, "depends on *dig_test.B provided by")
+		assert.Contains(t, msg, "depends on *dig_test.C provided by")
+
+		// Paths that start at the same function are reported once.
+		err = c.Verify(func(*B, *C) {})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "found 1 problem in the dependency graph:")
+	})
+
+	t.Run("named values", func(t *testing.T) {
+		t.Parallel()
+
+		type params struct {
+			dig.In
+
+			A *A `name:"primary"`
+		}
+
+		c := digtest.New(t)
+		c.RequireProvide(func() *A { return nil })
+		c.RequireProvide(func(params) *B { return nil })
+
+		err := c.Verify(func(*B) {})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `missing type: *dig_test.A[name="primary"]`)
+	})
+
+	t.Run("optional dependencies", func(t *testing.T) {
+		t.Parallel()
+
+		type params struct {
+			dig.In
+
+			B *B `optional:"true"`
+		}
+
+		c := digtest.New(t)
+		c.RequireProvide(func(*A) *B { return nil })
+		c.RequireProvide(func(params) *C { return nil })
+
+		assert.NoError(t, c.Verify(func(*C) {}))
+	})
+
+	t.Run("context parameters", func(t *testing.T) {
+		t.Parallel()
+
+		type params struct {
+			dig.In
+
+			Ctx context.Context `name:"request"`
+		}
+
+		c := digtest.New(t)
+		c.RequireProvide(func(context.Context) *A { return nil })
+		assert.NoError(t, c.Verify(func(context.Context, *A) {}))
+		assert.NoError(t, c.Verify())
+
+		// Named contexts are never the context of the Invoke.
+		c.RequireProvide(func(params) *B { return nil })
+
+		err := c.Verify(func(*B) {})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `missing type: context.Context[name="request"]`)
+	})
+
+	t.Run("all constructors", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func(*A) *B { return nil })
+		c.Scope("child").RequireProvide(func(*C) *D { return nil })
+
+		assert.NoError(t, c.Verify(func() {}))
+
+		err := c.Verify()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing type: *dig_test.A")
+		assert.Contains(t, err.Error(), "missing type: *dig_test.C")
+	})
+
+	t.Run("decorators", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() *A { return nil })
+		c.RequireDecorate(func(*A, *B) *A { return nil })
+
+		err := c.Verify(func(*A) {})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing type: *dig_test.B")
+	})
+
+	t.Run("value groups", func(t *testing.T) {
+		t.Parallel()
+
+		type params struct {
+			dig.In
+
+			As []*A `group:"as"`
+		}
+
+		c := digtest.New(t)
+		c.RequireProvide(func(*B) *A { return nil }, dig.Group("as"))
+		c.RequireProvide(func(params) *C { return nil })
+
+		err := c.Verify(func(*C) {})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing type: *dig_test.B")
+	})
+
+	t.Run("cycles", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t, dig.DeferAcyclicVerification())
+		c.RequireProvide(func(*B) *A { return nil })
+		c.RequireProvide(func(*A, *D) *B { return nil })
+		c.RequireProvide(func(*C) *D { return nil })
+
+		err := c.Verify(func(*A) {})
+		require.Error(t, err)
+		assert.True(t, dig.IsCycleDetected(err))
+
+		msg := err.Error()
+		assert.Contains(t, msg, "found 2 problems in the dependency graph:")
+		assert.Contains(t, msg, "missing type: *dig_test.C")
+		assert.Contains(t, msg, "*dig_test.A provided by")
+		assert.Contains(t, msg, "depends on *dig_test.B provided by")
+	})
+
+	t.Run("lazy dependencies may be cyclic", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func(dig.Lazy[*B]) *A { return nil })
+		c.RequireProvide(func(*A, dig.Lazy[*D]) *B { return nil })
+
+		err := c.Verify(func(*A) {})
+		require.Error(t, err)
+		assert.False(t, dig.IsCycleDetected(err))
+		assert.Contains(t, err.Error(), "missing type: *dig_test.D")
+	})
+
+	t.Run("per-scope constructors", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func(*A) *B { return nil }, dig.PerScope())
+
+		s := c.Scope("child")
+		s.RequireProvide(func() *A { return nil })
+
+		assert.NoError(t, s.Verify(func(*B) {}))
+		assert.Error(t, c.Verify(func(*B) {}))
+	})
+
+	t.Run("multiline", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func(*A) *B { return nil })
+
+		err := c.Verify(func(*B) {})
+		require.Error(t, err)
+		msg := fmt.Sprintf("%+v", err)
+		assert.Contains(t, msg, "found 1 problem in the dependency graph:\n[1] ")
+		assert.Contains(t, msg, "missing type:\n    \t- *dig_test.A (did you mean to Provide it?)")
+	})
+}
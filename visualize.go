@@ -24,7 +24,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"reflect"
 	"strconv"
+	"strings"
+	"time"
 
 	"go.uber.org/dig/internal/dot"
 )
@@ -36,6 +39,25 @@ type VisualizeOption interface {
 
 type visualizeOptions struct {
 	VisualizeError error
+	Format         GraphFormat
+
+	Roots        []reflect.Type
+	DependentsOf []reflect.Type
+	Scopes       []string
+	Packages     []string
+
+	// MaxDepth is negative if there is no limit.
+	MaxDepth int
+
+	Runtime bool
+}
+
+func newVisualizeOptions(opts []VisualizeOption) visualizeOptions {
+	options := visualizeOptions{MaxDepth: -1}
+	for _, o := range opts {
+		o.applyVisualizeOption(&options)
+	}
+	return options
 }
 
 // VisualizeError includes a visualization of the given error in the output of
@@ -61,28 +83,274 @@ func (o visualizeErrorOption) applyVisualizeOption(opt *visualizeOptions) {
 	opt.VisualizeError = o.err
 }
 
-func updateGraph(dg *dot.Graph, err error) error {
-	var errs []errVisualizer
-	// Unwrap error to find the root cause.
-	for {
-		if ev, ok := err.(errVisualizer); ok {
-			errs = append(errs, ev)
+// VisualizeFormat is a VisualizeOption that specifies the format in which
+// Visualize writes the graph. By default, graphs are written in the
+// Graphviz DOT format.
+//
+// For example, the following writes the graph as a Mermaid flowchart that
+// can be embedded in Markdown documents.
+//
+//	dig.Visualize(c, w, dig.VisualizeFormat(dig.GraphFormatMermaid))
+func VisualizeFormat(f GraphFormat) VisualizeOption {
+	return visualizeFormatOption{f}
+}
+
+type visualizeFormatOption struct{ format GraphFormat }
+
+func (o visualizeFormatOption) String() string {
+	return fmt.Sprintf("VisualizeFormat(%v)", o.format)
+}
+
+func (o visualizeFormatOption) applyVisualizeOption(opt *visualizeOptions) {
+	opt.Format = o.format
+}
+
+// VisualizeRoots is a VisualizeOption that limits the graph to the
+// constructors of the given types and the constructors they depend on,
+// directly or transitively. Types are matched regardless of their names
+// and value groups.
+//
+//	dig.Visualize(c, w, dig.VisualizeRoots(reflect.TypeOf(&http.Server{})))
+//
+// If used together with VisualizeDependentsOf, the graph includes the
+// constructors selected by either option.
+func VisualizeRoots(types ...reflect.Type) VisualizeOption {
+	return visualizeRootsOption(types)
+}
+
+type visualizeRootsOption []reflect.Type
+
+func (o visualizeRootsOption) String() string {
+	return fmt.Sprintf("VisualizeRoots(%v)", joinTypes(o))
+}
+
+func (o visualizeRootsOption) applyVisualizeOption(opt *visualizeOptions) {
+	opt.Roots = append(opt.Roots, o...)
+}
+
+// VisualizeDependentsOf is a VisualizeOption that limits the graph to the
+// constructors of the given type and the constructors that depend on it,
+// directly or transitively. The type is matched regardless of its name and
+// value group.
+//
+// If used together with VisualizeRoots, the graph includes the
+// constructors selected by either option.
+func VisualizeDependentsOf(t reflect.Type) VisualizeOption {
+	return visualizeDependentsOfOption{t}
+}
+
+type visualizeDependentsOfOption struct{ t reflect.Type }
+
+func (o visualizeDependentsOfOption) String() string {
+	return fmt.Sprintf("VisualizeDependentsOf(%v)", o.t)
+}
+
+func (o visualizeDependentsOfOption) applyVisualizeOption(opt *visualizeOptions) {
+	opt.DependentsOf = append(opt.DependentsOf, o.t)
+}
+
+// VisualizeMaxDepth is a VisualizeOption that limits how far from the
+// types passed to VisualizeRoots and VisualizeDependentsOf the graph
+// extends. With a depth of 0, only the constructors of these types are
+// included; with a depth of 1, their direct dependencies or dependents
+// are included as well, and so on. A negative depth means no limit.
+//
+// This option has no effect without VisualizeRoots or
+// VisualizeDependentsOf.
+func VisualizeMaxDepth(n int) VisualizeOption {
+	return visualizeMaxDepthOption(n)
+}
+
+type visualizeMaxDepthOption int
+
+func (o visualizeMaxDepthOption) String() string {
+	return fmt.Sprintf("VisualizeMaxDepth(%d)", int(o))
+}
+
+func (o visualizeMaxDepthOption) applyVisualizeOption(opt *visualizeOptions) {
+	opt.MaxDepth = int(o)
+}
+
+// VisualizeScope is a VisualizeOption that limits the graph to the
+// constructors and decorators provided to the Scope with the given name
+// and to its descendants. The root Scope of a Container has an empty name.
+//
+// If specified multiple times, the graph includes the constructors of all
+// the named Scopes.
+func VisualizeScope(name string) VisualizeOption {
+	return visualizeScopeOption(name)
+}
+
+type visualizeScopeOption string
+
+func (o visualizeScopeOption) String() string {
+	return fmt.Sprintf("VisualizeScope(%q)", string(o))
+}
+
+func (o visualizeScopeOption) applyVisualizeOption(opt *visualizeOptions) {
+	opt.Scopes = append(opt.Scopes, string(o))
+}
+
+// VisualizePackages is a VisualizeOption that limits the graph to the
+// constructors and decorators defined in packages whose import paths start
+// with one of the given prefixes.
+//
+//	dig.Visualize(c, w, dig.VisualizePackages("example.com/myapp/"))
+func VisualizePackages(prefixes ...string) VisualizeOption {
+	return visualizePackagesOption(prefixes)
+}
+
+type visualizePackagesOption []string
+
+func (o visualizePackagesOption) String() string {
+	return fmt.Sprintf("VisualizePackages(%v)", strings.Join(o, ", "))
+}
+
+func (o visualizePackagesOption) applyVisualizeOption(opt *visualizeOptions) {
+	opt.Packages = append(opt.Packages, o...)
+}
+
+// VisualizeRuntime is a VisualizeOption that annotates the constructors in
+// the graph with their runtime state: whether they were called, how many
+// times, and how long these calls took in total. Constructors are filled
+// with a color that gets warmer as their runtime gets closer to that of the
+// slowest constructor, and constructors that were never called are grayed
+// out. Only the time spent in the constructors themselves is counted, not
+// the time spent building their dependencies.
+//
+// Visualizing the container after it was used, for example after the
+// application started, shows where its startup time went.
+//
+// This option only affects the DOT format.
+func VisualizeRuntime() VisualizeOption {
+	return visualizeRuntimeOption{}
+}
+
+type visualizeRuntimeOption struct{}
+
+func (visualizeRuntimeOption) String() string {
+	return "VisualizeRuntime()"
+}
+
+func (visualizeRuntimeOption) applyVisualizeOption(opt *visualizeOptions) {
+	opt.Runtime = true
+}
+
+func joinTypes(types []reflect.Type) string {
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = fmt.Sprint(t)
+	}
+	return strings.Join(names, ", ")
+}
+
+// filterGraph removes the constructors and decorators that were not
+// selected by the options from the graph.
+func filterGraph(dg *dot.Graph, opts *visualizeOptions) {
+	var (
+		ctorFilters []func(*dot.Ctor) bool
+		dcorFilters []func(*dot.Decorator) bool
+	)
+
+	if len(opts.Roots) > 0 || len(opts.DependentsOf) > 0 {
+		selected := make(map[dot.CtorID]struct{})
+		visit := func(c *dot.Ctor) { selected[c.ID] = struct{}{} }
+
+		var roots []*dot.Ctor
+		for _, t := range opts.Roots {
+			roots = append(roots, dg.Providers(t)...)
+		}
+		dg.Walk(roots, false /* reverse */, opts.MaxDepth, visit)
+
+		for _, t := range opts.DependentsOf {
+			dg.Walk(dg.Providers(t), true /* reverse */, opts.MaxDepth, visit)
+
+			// Consumers are one edge away from the type even if nothing
+			// provides it.
+			if opts.MaxDepth != 0 {
+				dg.Walk(dg.Consumers(t), true /* reverse */, opts.MaxDepth-1, visit)
+			}
+		}
+
+		ctorFilters = append(ctorFilters, func(c *dot.Ctor) bool {
+			_, ok := selected[c.ID]
+			return ok
+		})
+	}
+
+	if len(opts.Scopes) > 0 {
+		// Scopes are numbered in tree order, so parents come before their
+		// children.
+		inScope := make(map[int]bool)
+		for _, s := range dg.Scopes {
+			for _, name := range opts.Scopes {
+				if s.Name == name {
+					inScope[s.ID] = true
+				}
+			}
+			if s.Parent >= 0 && inScope[s.Parent] {
+				inScope[s.ID] = true
+			}
 		}
-		e := errors.Unwrap(err)
-		if e == nil {
-			break
+
+		ctorFilters = append(ctorFilters, func(c *dot.Ctor) bool { return inScope[c.Scope] })
+		dcorFilters = append(dcorFilters, func(d *dot.Decorator) bool { return inScope[d.Scope] })
+	}
+
+	if len(opts.Packages) > 0 {
+		inPackages := func(pkg string) bool {
+			for _, prefix := range opts.Packages {
+				if strings.HasPrefix(pkg, prefix) {
+					return true
+				}
+			}
+			return false
 		}
-		err = e
+
+		ctorFilters = append(ctorFilters, func(c *dot.Ctor) bool { return inPackages(c.Package) })
+		dcorFilters = append(dcorFilters, func(d *dot.Decorator) bool { return inPackages(d.Package) })
 	}
 
+	if len(ctorFilters) == 0 {
+		return
+	}
+
+	dg.Retain(func(c *dot.Ctor) bool {
+		for _, f := range ctorFilters {
+			if !f(c) {
+				return false
+			}
+		}
+		return true
+	})
+
+	decorators := dg.Decorators[:0]
+	for _, d := range dg.Decorators {
+		keep := true
+		for _, f := range dcorFilters {
+			keep = keep && f(d)
+		}
+		if keep {
+			decorators = append(decorators, d)
+		}
+	}
+	dg.Decorators = decorators
+}
+
+func updateGraph(dg *dot.Graph, err error) error {
+	chains := errVisualizerChains(err, nil)
+
 	// If there are no errVisualizers included, we do not modify the graph.
-	if len(errs) == 0 {
+	if len(chains) == 0 {
 		return nil
 	}
 
-	// We iterate in reverse because the last element is the root cause.
-	for i := len(errs) - 1; i >= 0; i-- {
-		errs[i].updateGraph(dg)
+	for _, errs := range chains {
+		// We iterate in reverse because the last element is the root cause.
+		dg.StartFailureChain()
+		for i := len(errs) - 1; i >= 0; i-- {
+			errs[i].updateGraph(dg)
+		}
 	}
 
 	// Remove non-error entries from the graph for readability.
@@ -91,34 +359,93 @@ func updateGraph(dg *dot.Graph, err error) error {
 	return nil
 }
 
+// errVisualizerChains unwraps err to find the errVisualizers on the way to
+// its root cause, following them after the given ones. Errors that wrap the
+// errors of multiple dependencies lead to one chain for each of them.
+func errVisualizerChains(err error, chain []errVisualizer) [][]errVisualizer {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if ev, ok := err.(errVisualizer); ok {
+			// Copy the chain so that branches don't share it.
+			chain = append(chain[:len(chain):len(chain)], ev)
+		}
+		if me, ok := err.(interface{ Unwrap() []error }); ok {
+			var chains [][]errVisualizer
+			for _, e := range me.Unwrap() {
+				chains = append(chains, errVisualizerChains(e, chain)...)
+			}
+			return chains
+		}
+	}
+	if len(chain) == 0 {
+		return nil
+	}
+	return [][]errVisualizer{chain}
+}
+
 // Visualize parses the graph in Container c into DOT format and writes it to
-// io.Writer w.
+// io.Writer w. Use the VisualizeFormat option to write it in another format.
 func Visualize(c *Container, w io.Writer, opts ...VisualizeOption) error {
-	dg := c.createGraph()
+	options := newVisualizeOptions(opts)
+	write, err := graphWriter(&options)
+	if err != nil {
+		return err
+	}
 
-	var options visualizeOptions
-	for _, o := range opts {
-		o.applyVisualizeOption(&options)
+	dg, err := c.createVisualizeGraph(&options)
+	if err != nil {
+		return err
 	}
+	return write(w, dg)
+}
 
-	if options.VisualizeError != nil {
-		if err := updateGraph(dg, options.VisualizeError); err != nil {
-			return err
+// createVisualizeGraph creates the graph of the Container and updates it
+// according to the options.
+func (c *Container) createVisualizeGraph(opts *visualizeOptions) (*dot.Graph, error) {
+	dg := c.createGraph()
+	if opts.VisualizeError != nil {
+		if err := updateGraph(dg, opts.VisualizeError); err != nil {
+			return nil, err
 		}
 	}
-
-	visualizeGraph(w, dg)
-	return nil
+	filterGraph(dg, opts)
+	return dg, nil
 }
 
 func visualizeGraph(w io.Writer, dg *dot.Graph) {
+	writeDOT(w, dg, false /* runtime */)
+}
+
+// writeDOT writes the graph in the DOT format. If runtime is set,
+// constructors are annotated with their runtime state.
+func writeDOT(w io.Writer, dg *dot.Graph, runtime bool) {
+	dw := newDotWriter(w, dg)
+	if runtime {
+		dw.runtime = true
+		for _, c := range dg.Ctors {
+			if c.Runtime > dw.maxRuntime {
+				dw.maxRuntime = c.Runtime
+			}
+		}
+	}
+
 	w.Write([]byte("digraph {\n\trankdir=RL;\n\tgraph [compound=true];\n"))
 	for _, g := range dg.Groups {
 		visualizeGroup(w, g)
 	}
-	for idx, c := range dg.Ctors {
-		visualizeCtor(w, idx, c)
+
+	// Constructors and decorators of the root Scope are written at the top
+	// level, each followed by its edges. Those of child Scopes are written
+	// in nested clusters, and their edges are written after the outermost
+	// cluster so that the nodes they point to are not pulled into it.
+	dw.writeClusters(0, "\t", true /* withEdges */)
+	for _, child := range dw.children[0] {
+		if dw.empty(child) {
+			continue
+		}
+		dw.writeScope(child, "\t")
+		dw.writeEdges(child)
 	}
+
 	for _, f := range dg.Failed.TransitiveFailures {
 		fmt.Fprintf(w, "\t%s [color=orange];\n", strconv.Quote(f.String()))
 	}
@@ -135,47 +462,320 @@ func visualizeGroup(w io.Writer, g *dot.Group) {
 	}
 }
 
-func visualizeCtor(w io.Writer, index int, c *dot.Ctor) {
-	fmt.Fprintf(w, "\tsubgraph cluster_%d {\n", index)
+// dotWriter writes the constructors, decorators and Scopes of a dot.Graph
+// in the DOT format.
+type dotWriter struct {
+	*scopeTree
+
+	w io.Writer
+
+	// runtime is set if constructors are annotated with their runtime
+	// state. maxRuntime is the runtime of the slowest constructor.
+	runtime    bool
+	maxRuntime time.Duration
+}
+
+func newDotWriter(w io.Writer, dg *dot.Graph) *dotWriter {
+	return &dotWriter{scopeTree: newScopeTree(dg), w: w}
+}
+
+// scopeTree indexes the Scopes and decorators of a dot.Graph to find the
+// nodes that constructors and decorators consume in each Scope.
+type scopeTree struct {
+	dg *dot.Graph
+
+	// Parent and child Scopes of each Scope, by ID.
+	parents  map[int]int
+	children map[int][]int
+
+	// decorated maps the values decorated in each Scope to the IDs of the
+	// nodes of their decorated versions.
+	decorated map[dotScopedKey]string
+}
+
+type dotScopedKey struct {
+	scope int
+	key   string
+}
+
+func newScopeTree(dg *dot.Graph) *scopeTree {
+	st := &scopeTree{
+		dg:        dg,
+		parents:   make(map[int]int),
+		children:  make(map[int][]int),
+		decorated: make(map[dotScopedKey]string),
+	}
+	for _, s := range dg.Scopes {
+		st.parents[s.ID] = s.Parent
+		if s.Parent >= 0 {
+			st.children[s.Parent] = append(st.children[s.Parent], s.ID)
+		}
+	}
+	for idx, d := range dg.Decorators {
+		for _, r := range d.Results {
+			k := dotNodeKey(r.Node)
+			st.decorated[dotScopedKey{d.Scope, k}] = fmt.Sprintf("%v[decorator=%d]", k, idx)
+		}
+	}
+	return st
+}
+
+// dotNodeKey returns the ID of the node of the value that a decorator
+// parameter or result refers to. Decorators of value groups consume and
+// produce the whole group.
+func dotNodeKey(n *dot.Node) string {
+	if n.Group == "" {
+		return (&dot.Param{Node: n}).String()
+	}
+	t := n.Type
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	return (&dot.Group{Type: t, Name: n.Group}).String()
+}
+
+// target returns the ID of the node that a consumer in the given Scope
+// receives for a value: the value as decorated in that Scope or in its
+// closest ancestor, or the value itself if it is not decorated.
+func (st *scopeTree) target(scope int, key string) string {
+	for {
+		if id, ok := st.decorated[dotScopedKey{scope, key}]; ok {
+			return id
+		}
+		parent, ok := st.parents[scope]
+		if !ok || parent < 0 {
+			return key
+		}
+		scope = parent
+	}
+}
+
+// decoratorTarget returns the ID of the node that the decorator d receives
+// for its parameter p.
+func (st *scopeTree) decoratorTarget(d *dot.Decorator, p *dot.Param) string {
+	k := dotNodeKey(p.Node)
+	for _, r := range d.Results {
+		if dotNodeKey(r.Node) != k {
+			continue
+		}
+
+		// The value being decorated is the one visible from the parent
+		// Scope, which may have been decorated there too.
+		parent, ok := st.parents[d.Scope]
+		if !ok || parent < 0 {
+			return k
+		}
+		return st.target(parent, k)
+	}
+	return st.target(d.Scope, k)
+}
+
+// empty reports whether neither the Scope with the given ID nor its
+// descendants have constructors or decorators in the graph.
+func (st *scopeTree) empty(scope int) bool {
+	for _, c := range st.dg.Ctors {
+		if c.Scope == scope {
+			return false
+		}
+	}
+	for _, d := range st.dg.Decorators {
+		if d.Scope == scope {
+			return false
+		}
+	}
+	for _, child := range st.children[scope] {
+		if !st.empty(child) {
+			return false
+		}
+	}
+	return true
+}
+
+// writeScope writes the cluster of a child Scope, nested clusters
+// included, at the given indentation.
+func (dw *dotWriter) writeScope(id int, indent string) {
+	fmt.Fprintf(dw.w, "%ssubgraph cluster_scope_%d {\n", indent, id)
+	fmt.Fprintf(dw.w, "%s\tlabel = %s;\n", indent, strconv.Quote(dw.dg.Scopes[id].Name))
+	fmt.Fprintf(dw.w, "%s\tstyle=rounded;\n", indent)
+	dw.writeClusters(id, indent+"\t", false /* withEdges */)
+	for _, child := range dw.children[id] {
+		if !dw.empty(child) {
+			dw.writeScope(child, indent+"\t")
+		}
+	}
+	fmt.Fprintf(dw.w, "%s}\n", indent)
+}
+
+// writeClusters writes the clusters of the constructors and decorators of
+// the Scope with the given ID, optionally followed by their edges.
+func (dw *dotWriter) writeClusters(scope int, indent string, withEdges bool) {
+	for idx, c := range dw.dg.Ctors {
+		if c.Scope != scope {
+			continue
+		}
+		dw.writeCtor(indent, idx, c)
+		if withEdges {
+			dw.writeCtorEdges(idx, c)
+		}
+	}
+	for idx, d := range dw.dg.Decorators {
+		if d.Scope != scope {
+			continue
+		}
+		dw.writeDecorator(indent, idx, d)
+		if withEdges {
+			dw.writeDecoratorEdges(idx, d)
+		}
+	}
+}
+
+// writeEdges writes the edges of the constructors and decorators of the
+// Scope with the given ID and of its descendants.
+func (dw *dotWriter) writeEdges(scope int) {
+	for idx, c := range dw.dg.Ctors {
+		if c.Scope == scope {
+			dw.writeCtorEdges(idx, c)
+		}
+	}
+	for idx, d := range dw.dg.Decorators {
+		if d.Scope == scope {
+			dw.writeDecoratorEdges(idx, d)
+		}
+	}
+	for _, child := range dw.children[scope] {
+		dw.writeEdges(child)
+	}
+}
+
+func (dw *dotWriter) writeCtor(indent string, index int, c *dot.Ctor) {
+	w := dw.w
+	fmt.Fprintf(w, "%ssubgraph cluster_%d {\n", indent, index)
 	if c.Package != "" {
-		fmt.Fprintf(w, "\t\tlabel = %s;\n", strconv.Quote(c.Package))
+		fmt.Fprintf(w, "%s\tlabel = %s;\n", indent, strconv.Quote(c.Package))
+	}
+	label := c.Name
+	if c.Exported {
+		label += "\n(exported)"
+	}
+	if dw.runtime {
+		if c.Called {
+			label += fmt.Sprintf("\ncalls: %d, runtime: %v", c.Calls, c.Runtime)
+		} else {
+			label += "\nnot called"
+		}
+	}
+	fmt.Fprintf(w, "%s\tconstructor_%d [shape=plaintext label=%s];\n", indent, index, strconv.Quote(label))
+
+	var styles []string
+	if c.Transient {
+		styles = append(styles, "dashed")
+	}
+	if c.Exported {
+		styles = append(styles, "bold")
+	}
+	if dw.runtime && c.Called {
+		styles = append(styles, "filled")
+	}
+	switch len(styles) {
+	case 0:
+	case 1:
+		fmt.Fprintf(w, "%s\tstyle=%s;\n", indent, styles[0])
+	default:
+		fmt.Fprintf(w, "%s\tstyle=%s;\n", indent, strconv.Quote(strings.Join(styles, ",")))
+	}
+
+	if dw.runtime {
+		if c.Called {
+			fmt.Fprintf(w, "%s\tfillcolor=%s;\n", indent, strconv.Quote(dw.costColor(c.Runtime)))
+		} else if c.ErrorType == 0 {
+			fmt.Fprintf(w, "%s\tcolor=gray;\n", indent)
+			fmt.Fprintf(w, "%s\tfontcolor=gray;\n", indent)
+		}
 	}
-	fmt.Fprintf(w, "\t\tconstructor_%d [shape=plaintext label=%s];\n", index, strconv.Quote(c.Name))
 
 	if c.ErrorType != 0 {
-		fmt.Fprintf(w, "\t\tcolor=%s;\n", c.ErrorType.Color())
+		fmt.Fprintf(w, "%s\tcolor=%s;\n", indent, c.ErrorType.Color())
 	}
 	for _, r := range c.Results {
-		fmt.Fprintf(w, "\t\t%s [%s];\n", strconv.Quote(r.String()), r.Attributes())
+		fmt.Fprintf(w, "%s\t%s [%s];\n", indent, strconv.Quote(r.String()), r.Attributes())
 	}
-	fmt.Fprintf(w, "\t}\n")
+	fmt.Fprintf(w, "%s}\n", indent)
+}
+
+func (dw *dotWriter) writeCtorEdges(index int, c *dot.Ctor) {
 	for _, p := range c.Params {
-		var optionalStyle string
-		if p.Optional {
-			optionalStyle = " style=dashed"
+		fmt.Fprintf(dw.w, "\tconstructor_%d -> %s [ltail=cluster_%d%s];\n",
+			index, strconv.Quote(dw.target(c.Scope, p.String())), index, dotParamStyle(p))
+	}
+	for _, p := range c.GroupParams {
+		fmt.Fprintf(dw.w, "\tconstructor_%d -> %s [ltail=cluster_%d];\n",
+			index, strconv.Quote(dw.target(c.Scope, p.String())), index)
+	}
+}
+
+func (dw *dotWriter) writeDecorator(indent string, index int, d *dot.Decorator) {
+	w := dw.w
+	fmt.Fprintf(w, "%ssubgraph cluster_decorator_%d {\n", indent, index)
+	if d.Package != "" {
+		fmt.Fprintf(w, "%s\tlabel = %s;\n", indent, strconv.Quote(d.Package))
+	}
+	fmt.Fprintf(w, "%s\tdecorator_%d [shape=plaintext label=%s];\n", indent, index, strconv.Quote(d.Name))
+	fmt.Fprintf(w, "%s\tstyle=filled;\n", indent)
+	fmt.Fprintf(w, "%s\tfillcolor=lightgrey;\n", indent)
+	for _, r := range d.Results {
+		id := dw.decorated[dotScopedKey{d.Scope, dotNodeKey(r.Node)}]
+		attrs := r.Attributes()
+		if r.Group != "" {
+			attrs = (&dot.Group{Type: r.Type.Elem(), Name: r.Group}).Attributes()
 		}
+		fmt.Fprintf(w, "%s\t%s [%s];\n", indent, strconv.Quote(id), attrs)
+	}
+	fmt.Fprintf(w, "%s}\n", indent)
+}
 
-		fmt.Fprintf(w, "\tconstructor_%d -> %s [ltail=cluster_%d%s];\n", index, strconv.Quote(p.String()), index, optionalStyle)
+func (dw *dotWriter) writeDecoratorEdges(index int, d *dot.Decorator) {
+	for _, p := range d.Params {
+		fmt.Fprintf(dw.w, "\tdecorator_%d -> %s [ltail=cluster_decorator_%d%s];\n",
+			index, strconv.Quote(dw.decoratorTarget(d, p)), index, dotParamStyle(p))
 	}
-	for _, p := range c.GroupParams {
-		fmt.Fprintf(w, "\tconstructor_%d -> %s [ltail=cluster_%d];\n", index, strconv.Quote(p.String()), index)
+}
+
+// costColor returns the fill color of a constructor that ran for the given
+// time, relative to the slowest constructor of the graph.
+func (dw *dotWriter) costColor(runtime time.Duration) string {
+	if dw.maxRuntime <= 0 {
+		return "#fef0d9"
+	}
+	switch ratio := float64(runtime) / float64(dw.maxRuntime); {
+	case ratio >= 2.0/3:
+		return "#fc8d59"
+	case ratio >= 1.0/3:
+		return "#fdcc8a"
+	default:
+		return "#fef0d9"
+	}
+}
+
+func dotParamStyle(p *dot.Param) string {
+	switch {
+	case p.Optional:
+		return " style=dashed"
+	case p.Lazy:
+		return " style=dotted"
+	default:
+		return ""
 	}
 }
 
 // CanVisualizeError returns true if the error is an errVisualizer.
 func CanVisualizeError(err error) bool {
-	for {
-		if _, ok := err.(errVisualizer); ok {
-			return true
-		}
-		e := errors.Unwrap(err)
-		if e == nil {
-			break
+	var ok bool
+	walkErrors(err, func(err error) {
+		if _, isVisualizer := err.(errVisualizer); isVisualizer {
+			ok = true
 		}
-		err = e
-	}
-
-	return false
+	})
+	return ok
 }
 
 func (c *Container) createGraph() *dot.Graph {
@@ -183,29 +783,65 @@ func (c *Container) createGraph() *dot.Graph {
 }
 
 func (s *Scope) createGraph() *dot.Graph {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	dg := dot.NewGraph()
 
-	s.addNodes(dg)
+	// Scopes are numbered in the order of the tree so that parents are
+	// always added before their children.
+	scopeIDs := make(map[*Scope]int)
+	for _, sc := range s.appendSubscopes(nil) {
+		parent := -1
+		if id, ok := scopeIDs[sc.parentScope]; ok {
+			parent = id
+		}
+		scopeIDs[sc] = dg.AddScope(sc.name, parent)
+	}
+
+	s.addNodes(dg, scopeIDs)
 
 	return dg
 }
 
-func (s *Scope) addNodes(dg *dot.Graph) {
+func (s *Scope) addNodes(dg *dot.Graph, scopeIDs map[*Scope]int) {
 	for _, n := range s.nodes {
-		dg.AddCtor(newDotCtor(n), n.paramList.DotParam(), n.resultList.DotResult())
+		dg.AddCtor(newDotCtor(n, scopeIDs), n.paramList.DotParam(), n.resultList.DotResult())
+	}
+
+	for _, d := range s.decoratorNodes() {
+		dg.AddDecorator(newDotDecorator(d, scopeIDs), d.params.DotParam(), d.results.DotResult())
 	}
 
 	for _, cs := range s.childScopes {
-		cs.addNodes(dg)
+		cs.addNodes(dg, scopeIDs)
 	}
 }
 
-func newDotCtor(n *constructorNode) *dot.Ctor {
+func newDotCtor(n *constructorNode, scopeIDs map[*Scope]int) *dot.Ctor {
 	return &dot.Ctor{
-		ID:      n.id,
-		Name:    n.location.Name,
-		Package: n.location.Package,
-		File:    n.location.File,
-		Line:    n.location.Line,
+		ID:        n.id,
+		Name:      n.location.Name,
+		Package:   n.location.Package,
+		File:      n.location.File,
+		Line:      n.location.Line,
+		Transient: n.transient,
+		PerScope:  n.perScope,
+		Exported:  n.exported,
+		Scope:     scopeIDs[n.OrigScope()],
+		Called:    n.stats.Calls() > 0 || n.instantiated(),
+		Calls:     n.stats.Calls(),
+		Runtime:   n.stats.Runtime(),
+	}
+}
+
+func newDotDecorator(d *decoratorNode, scopeIDs map[*Scope]int) *dot.Decorator {
+	return &dot.Decorator{
+		ID:      d.id,
+		Name:    d.location.Name,
+		Package: d.location.Package,
+		File:    d.location.File,
+		Line:    d.location.Line,
+		Scope:   scopeIDs[d.s],
 	}
 }
@@ -39,15 +39,20 @@ func VerifyVisualization(t *testing.T, testname string, c *Container, opts ...Vi
 	got := b.Bytes()
 	got = bytes.ReplaceAll(got, []byte("\r\n"), []byte("\n")) // normalize line endings
 
-	dotFile := filepath.Join("testdata", testname+".dot")
+	options := newVisualizeOptions(opts)
+	ext := ".dot"
+	if options.Format == GraphFormatMermaid {
+		ext = ".mmd"
+	}
+	goldenFile := filepath.Join("testdata", testname+ext)
 
 	if *generate {
-		err := os.WriteFile(dotFile, got, 0o644)
+		err := os.WriteFile(goldenFile, got, 0o644)
 		require.NoError(t, err)
 		return
 	}
 
-	want, err := os.ReadFile(dotFile)
+	want, err := os.ReadFile(goldenFile)
 	require.NoError(t, err)
 	want = bytes.ReplaceAll(want, []byte("\r\n"), []byte("\n")) // normalize line endings
 
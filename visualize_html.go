@@ -0,0 +1,50 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig
+
+import (
+	_ "embed" // needed for go:embed
+	"html/template"
+	"io"
+)
+
+//go:embed visualize_html.tmpl
+var _visualizeHTML string
+
+var _visualizeHTMLTemplate = template.Must(template.New("dig").Parse(_visualizeHTML))
+
+// VisualizeHTML writes the graph in Container c to io.Writer w as a
+// self-contained HTML page for exploring large graphs in a browser.
+//
+// The page embeds the graph and everything needed to display it, so it can
+// be opened offline. It lists the constructors of the container and allows
+// searching them by type, filtering them by scope, and expanding the
+// dependencies and dependents of each of them. When VisualizeError is used,
+// the failing constructors and the root causes of the error are
+// highlighted. The VisualizeFormat option is ignored.
+func VisualizeHTML(c *Container, w io.Writer, opts ...VisualizeOption) error {
+	options := newVisualizeOptions(opts)
+	dg, err := c.createVisualizeGraph(&options)
+	if err != nil {
+		return err
+	}
+	return _visualizeHTMLTemplate.Execute(w, newJSONGraph(dg))
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dig_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/dig"
+	"go.uber.org/dig/internal/digtest"
+)
+
+func TestVisualizeHTML(t *testing.T) {
+	t.Parallel()
+
+	type A struct{}
+	type B struct{}
+
+	type graph struct {
+		Constructors []struct {
+			Name    string `json:"name"`
+			Results []struct {
+				Type string `json:"type"`
+				Name string `json:"name"`
+			} `json:"results"`
+			Error string `json:"error"`
+		} `json:"constructors"`
+		Failures *struct {
+			RootCauses []struct {
+				Type string `json:"type"`
+			} `json:"rootCauses"`
+		} `json:"failures"`
+	}
+
+	// graphData extracts the graph embedded in the page.
+	graphData := func(t *testing.T, page string) graph {
+		const start = `<script id="dig-graph" type="application/json">`
+		i := strings.Index(page, start)
+		require.True(t, i >= 0, "graph data not found")
+		data := page[i+len(start):]
+		data = data[:strings.Index(data, "</script>")]
+
+		var g graph
+		require.NoError(t, json.Unmarshal([]byte(data), &g))
+		return g
+	}
+
+	t.Run("self-contained", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() A { return A{} })
+		c.RequireProvide(func(A) B { return B{} })
+
+		var buf bytes.Buffer
+		require.NoError(t, dig.VisualizeHTML(c.Container, &buf))
+		page := buf.String()
+
+		assert.True(t, strings.HasPrefix(page, "<!DOCTYPE html>"))
+		assert.NotContains(t, page, "src=", "page must not load external resources")
+		assert.NotContains(t, page, "href=", "page must not load external resources")
+
+		g := graphData(t, page)
+		require.Len(t, g.Constructors, 2)
+		assert.Equal(t, "dig_test.A", g.Constructors[0].Results[0].Type)
+		assert.Equal(t, "dig_test.B", g.Constructors[1].Results[0].Type)
+	})
+
+	t.Run("escapes graph data", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func() A { return A{} }, dig.Name("</script><script>alert(1)"))
+
+		var buf bytes.Buffer
+		require.NoError(t, dig.VisualizeHTML(c.Container, &buf))
+		assert.NotContains(t, buf.String(), "<script>alert(1)")
+
+		g := graphData(t, buf.String())
+		require.Len(t, g.Constructors, 1)
+		assert.Equal(t, "</script><script>alert(1)", g.Constructors[0].Results[0].Name)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		c := digtest.New(t)
+		c.RequireProvide(func(A) B { return B{} })
+		err := c.Invoke(func(B) {})
+		require.Error(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, dig.VisualizeHTML(c.Container, &buf, dig.VisualizeError(err)))
+
+		g := graphData(t, buf.String())
+		require.Len(t, g.Constructors, 1)
+		assert.Equal(t, "transitiveFailure", g.Constructors[0].Error)
+		require.NotNil(t, g.Failures)
+		require.Len(t, g.Failures.RootCauses, 1)
+		assert.Equal(t, "dig_test.A", g.Failures.RootCauses[0].Type)
+	})
+}
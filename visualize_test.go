@@ -28,10 +28,12 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/dig"
+	"go.uber.org/dig/internal/digclock"
 	"go.uber.org/dig/internal/digtest"
 	"go.uber.org/dig/internal/dot"
 )
@@ -640,6 +642,33 @@ func TestVisualize(t *testing.T) {
 
 		dig.VerifyVisualization(t, "missingDep", c.Container, dig.VisualizeError(err))
 	})
+
+	t.Run("transient constructor", func(t *testing.T) {
+		c := digtest.New(t)
+		c.RequireProvide(func() t1 { return t1{} }, dig.Transient())
+		c.RequireProvide(func(t1) t2 { return t2{} })
+
+		dig.VerifyVisualization(t, "transient", c.Container)
+	})
+
+	t.Run("lazy params", func(t *testing.T) {
+		c := digtest.New(t)
+		c.RequireProvide(func() t1 { return t1{} })
+		c.RequireProvide(func(dig.Lazy[t1]) t2 { return t2{} })
+
+		dig.VerifyVisualization(t, "lazy", c.Container)
+	})
+
+	t.Run("collected errors", func(t *testing.T) {
+		c := digtest.New(t)
+		c.RequireProvide(func() (t1, error) { return t1{}, errors.New("great sadness") })
+		c.RequireProvide(func() (t2, error) { return t2{}, errors.New("great sadness") })
+		c.RequireProvide(func(t1) t3 { return t3{} })
+		c.RequireProvide(func(t3) t4 { return t4{} })
+		err := c.Invoke(func(t2, t4) {}, dig.CollectErrors())
+
+		dig.VerifyVisualization(t, "collect_errors", c.Container, dig.VisualizeError(err))
+	})
 }
 
 func TestVisualizeErrorString(t *testing.T) {
@@ -659,3 +688,288 @@ func TestVisualizeErrorString(t *testing.T) {
 		assert.Equal(t, "VisualizeError(great sadness)", fmt.Sprint(opt))
 	})
 }
+
+func TestVisualizeMermaid(t *testing.T) {
+	type t1 struct{}
+	type t2 struct{}
+	type t3 struct{}
+	type t4 struct{}
+
+	t.Parallel()
+
+	mermaid := dig.VisualizeFormat(dig.GraphFormatMermaid)
+
+	t.Run("empty", func(t *testing.T) {
+		c := digtest.New(t)
+
+		dig.VerifyVisualization(t, "mermaid_empty", c.Container, mermaid)
+	})
+
+	t.Run("simple", func(t *testing.T) {
+		c := digtest.New(t)
+		c.RequireProvide(func() (t1, t2) { return t1{}, t2{} })
+		c.RequireProvide(func(A t1, B t2) (t3, t4) { return t3{}, t4{} })
+
+		dig.VerifyVisualization(t, "mermaid_simple", c.Container, mermaid)
+	})
+
+	t.Run("named and grouped", func(t *testing.T) {
+		type in struct {
+			dig.In
+
+			A t1   `name:"foo"`
+			B []t2 `group:"bar"`
+		}
+		type out struct {
+			dig.Out
+
+			B t2 `group:"bar"`
+		}
+
+		c := digtest.New(t)
+		c.RequireProvide(func() t1 { return t1{} }, dig.Name("foo"))
+		c.RequireProvide(func() out { return out{} })
+		c.RequireProvide(func() out { return out{} })
+		c.RequireProvide(func(in) t3 { return t3{} })
+
+		dig.VerifyVisualization(t, "mermaid_grouped", c.Container, mermaid)
+	})
+
+	t.Run("optional, lazy and transient", func(t *testing.T) {
+		type in struct {
+			dig.In
+
+			A t1 `optional:"true"`
+			B dig.Lazy[t2]
+		}
+
+		c := digtest.New(t)
+		c.RequireProvide(func() t1 { return t1{} })
+		c.RequireProvide(func() t2 { return t2{} }, dig.Transient())
+		c.RequireProvide(func(in) t3 { return t3{} })
+
+		dig.VerifyVisualization(t, "mermaid_edges", c.Container, mermaid)
+	})
+
+	t.Run("constructor error", func(t *testing.T) {
+		c := digtest.New(t)
+		c.RequireProvide(func() (t1, error) { return t1{}, errors.New("great sadness") })
+		c.RequireProvide(func(A t1) t2 { return t2{} })
+		c.RequireProvide(func(A t2) t3 { return t3{} })
+		err := c.Invoke(func(t3) {})
+
+		dig.VerifyVisualization(t, "mermaid_error", c.Container, mermaid, dig.VisualizeError(err))
+	})
+
+	t.Run("missing dependency", func(t *testing.T) {
+		c := digtest.New(t)
+		c.RequireProvide(func(A t1) t2 { return t2{} })
+		err := c.Invoke(func(t2) {})
+
+		dig.VerifyVisualization(t, "mermaid_missing", c.Container, mermaid, dig.VisualizeError(err))
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		c := digtest.New(t)
+		var buf bytes.Buffer
+		err := dig.Visualize(c.Container, &buf, dig.VisualizeFormat(dig.GraphFormat(42)))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown graph format")
+	})
+}
+
+func TestVisualizeFormatString(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "VisualizeFormat(DOT)", fmt.Sprint(dig.VisualizeFormat(dig.GraphFormatDOT)))
+	assert.Equal(t, "VisualizeFormat(Mermaid)", fmt.Sprint(dig.VisualizeFormat(dig.GraphFormatMermaid)))
+}
+
+func TestVisualizeFilter(t *testing.T) {
+	type A struct{}
+	type B struct{}
+	type C struct{}
+	type D struct{}
+	type E struct{}
+	type F struct{}
+
+	t.Parallel()
+
+	newContainer := func(t *testing.T) *digtest.Container {
+		c := digtest.New(t)
+		c.RequireProvide(func() A { return A{} })
+		c.RequireProvide(func(A) B { return B{} })
+		c.RequireProvide(func(B) C { return C{} })
+		c.RequireProvide(func(A) D { return D{} })
+		c.RequireProvide(func(C, D) E { return E{} })
+		c.Scope("child").RequireProvide(func(A) F { return F{} })
+		return c
+	}
+
+	typeOf := func(v interface{}) reflect.Type { return reflect.TypeOf(v) }
+
+	t.Run("roots", func(t *testing.T) {
+		c := newContainer(t)
+		dig.VerifyVisualization(t, "filter_roots", c.Container,
+			dig.VisualizeRoots(typeOf(C{})))
+	})
+
+	t.Run("dependents", func(t *testing.T) {
+		c := newContainer(t)
+		dig.VerifyVisualization(t, "filter_dependents", c.Container,
+			dig.VisualizeDependentsOf(typeOf(B{})))
+	})
+
+	t.Run("max depth", func(t *testing.T) {
+		c := newContainer(t)
+		dig.VerifyVisualization(t, "filter_max_depth", c.Container,
+			dig.VisualizeRoots(typeOf(E{})), dig.VisualizeMaxDepth(1))
+	})
+
+	t.Run("roots and dependents", func(t *testing.T) {
+		c := newContainer(t)
+		dig.VerifyVisualization(t, "filter_roots_and_dependents", c.Container,
+			dig.VisualizeRoots(typeOf(B{})), dig.VisualizeDependentsOf(typeOf(D{})))
+	})
+
+	t.Run("scope", func(t *testing.T) {
+		c := newContainer(t)
+		dig.VerifyVisualization(t, "filter_scope", c.Container,
+			dig.VisualizeScope("child"))
+	})
+
+	t.Run("packages", func(t *testing.T) {
+		c := newContainer(t)
+		dig.VerifyVisualization(t, "empty", c.Container,
+			dig.VisualizePackages("example.com/"))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		c := digtest.New(t)
+		c.RequireProvide(func() A { return A{} })
+		c.RequireProvide(func(A) (D, error) { return D{}, errors.New("great sadness") })
+		c.RequireProvide(func(D) E { return E{} })
+		err := c.Invoke(func(E) {})
+		require.Error(t, err)
+
+		dig.VerifyVisualization(t, "filter_error", c.Container,
+			dig.VisualizeError(err), dig.VisualizeRoots(typeOf(E{})), dig.VisualizeMaxDepth(0))
+	})
+}
+
+func TestVisualizeFilterString(t *testing.T) {
+	t.Parallel()
+
+	type A struct{}
+	type B struct{}
+
+	tests := []struct {
+		give dig.VisualizeOption
+		want string
+	}{
+		{dig.VisualizeRoots(reflect.TypeOf(A{}), reflect.TypeOf(B{})), "VisualizeRoots(dig_test.A, dig_test.B)"},
+		{dig.VisualizeDependentsOf(reflect.TypeOf(A{})), "VisualizeDependentsOf(dig_test.A)"},
+		{dig.VisualizeMaxDepth(2), "VisualizeMaxDepth(2)"},
+		{dig.VisualizeScope("child"), `VisualizeScope("child")`},
+		{dig.VisualizePackages("a/", "b/"), "VisualizePackages(a/, b/)"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, fmt.Sprint(tt.give))
+	}
+}
+
+func TestVisualizeDecoratorsAndScopes(t *testing.T) {
+	type A struct{}
+	type B struct{}
+	type C struct{}
+	type D struct{}
+	type E struct{}
+
+	t.Parallel()
+
+	t.Run("decorator", func(t *testing.T) {
+		c := digtest.New(t)
+		c.RequireProvide(func() A { return A{} })
+		c.RequireDecorate(func(A) A { return A{} })
+		c.RequireProvide(func(A) B { return B{} })
+
+		dig.VerifyVisualization(t, "decorator", c.Container)
+		dig.VerifyVisualization(t, "mermaid_decorator", c.Container, dig.VisualizeFormat(dig.GraphFormatMermaid))
+	})
+
+	t.Run("nested scopes", func(t *testing.T) {
+		c := digtest.New(t)
+		c.RequireProvide(func() A { return A{} })
+		c.RequireProvide(func(A) D { return D{} })
+
+		child := c.Scope("child")
+		child.RequireDecorate(func(A) A { return A{} })
+		child.RequireProvide(func(A) B { return B{} })
+		child.RequireProvide(func() E { return E{} }, dig.Export(true))
+
+		grandchild := child.Scope("grandchild")
+		grandchild.RequireDecorate(func(A, B) A { return A{} })
+		grandchild.RequireProvide(func(A, B, E) C { return C{} })
+
+		dig.VerifyVisualization(t, "nested_scopes", c.Container)
+		dig.VerifyVisualization(t, "mermaid_nested_scopes", c.Container, dig.VisualizeFormat(dig.GraphFormatMermaid))
+	})
+
+	t.Run("group decorator", func(t *testing.T) {
+		type in struct {
+			dig.In
+
+			As []A `group:"as"`
+		}
+		type out struct {
+			dig.Out
+
+			As []A `group:"as"`
+		}
+
+		c := digtest.New(t)
+		c.RequireProvide(func() A { return A{} }, dig.Group("as"))
+		c.RequireProvide(func() A { return A{} }, dig.Group("as"))
+		c.RequireDecorate(func(i in) out { return out{As: i.As} })
+		c.RequireProvide(func(in) B { return B{} })
+
+		dig.VerifyVisualization(t, "group_decorator", c.Container)
+		dig.VerifyVisualization(t, "mermaid_group_decorator", c.Container, dig.VisualizeFormat(dig.GraphFormatMermaid))
+	})
+}
+
+func TestVisualizeRuntime(t *testing.T) {
+	type A struct{}
+	type B struct{}
+	type C struct{}
+	type D struct{}
+	type E struct{}
+
+	t.Parallel()
+
+	clock := digclock.NewMock()
+	c := digtest.New(t, dig.SetClock(clock))
+	c.RequireProvide(func() A {
+		clock.Add(30 * time.Millisecond)
+		return A{}
+	})
+	c.RequireProvide(func(A) B {
+		clock.Add(15 * time.Millisecond)
+		return B{}
+	})
+	c.RequireProvide(func() C {
+		clock.Add(time.Millisecond)
+		return C{}
+	}, dig.Transient())
+	c.RequireProvide(func(B, C) D { return D{} })
+	c.RequireProvide(func(C) E { return E{} })
+
+	c.RequireInvoke(func(D, C) {})
+
+	dig.VerifyVisualization(t, "runtime", c.Container, dig.VisualizeRuntime())
+}
+
+func TestVisualizeRuntimeString(t *testing.T) {
+	assert.Equal(t, "VisualizeRuntime()", fmt.Sprint(dig.VisualizeRuntime()))
+}